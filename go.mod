@@ -1,5 +1,5 @@
 module github.com/Pusher91/webtruder
 
-go 1.18
+go 1.24.0
 
 require golang.org/x/time v0.14.0 // indirect
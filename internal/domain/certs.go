@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CertInfo is the leaf certificate a host presented on its first TLS handshake.
+type CertInfo struct {
+	Subject    string   `json:"subject"`
+	Issuer     string   `json:"issuer"`
+	NotBefore  string   `json:"notBefore"`
+	NotAfter   string   `json:"notAfter"`
+	SANs       []string `json:"sans,omitempty"` // DNS names, then IP addresses
+	SelfSigned bool     `json:"selfSigned,omitempty"`
+}
+
+// TargetSuggestion is a certificate SAN that is not yet a target of the scan.
+type TargetSuggestion struct {
+	Target     string   `json:"target"`
+	From       []string `json:"from"`                 // targets whose certificates list it
+	OutOfScope string   `json:"outOfScope,omitempty"` // why the scope would reject it
+}
+
+// HostCertMsg is emitted when a host's certificate is recorded, with the SAN targets it adds.
+type HostCertMsg struct {
+	ScanID      string             `json:"scanId"`
+	Target      string             `json:"target"`
+	Cert        CertInfo           `json:"cert"`
+	Suggestions []TargetSuggestion `json:"suggestions,omitempty"`
+}
+
+// SANSuggestions turns the certificate SANs recorded in meta into targets on the same scheme and
+// port as the host that presented them, leaving out hosts the scan already covers, wildcard and
+// IP entries. A nil scope skips the scope check.
+func (m *Meta) SANSuggestions(scope *Scope) []TargetSuggestion {
+	have := map[string]struct{}{}
+	for _, t := range m.Targets {
+		if k := originKey(t); k != "" {
+			have[k] = struct{}{}
+		}
+	}
+	for t := range m.Hosts {
+		if k := originKey(t); k != "" {
+			have[k] = struct{}{}
+		}
+	}
+
+	// Hosts are visited in order so the first spelling of a SAN target wins every time.
+	targets := make([]string, 0, len(m.Hosts))
+	for t := range m.Hosts {
+		targets = append(targets, t)
+	}
+	sort.Strings(targets)
+
+	byTarget := map[string]*TargetSuggestion{}
+	for _, t := range targets {
+		hm := m.Hosts[t]
+		if hm.Cert == nil {
+			continue
+		}
+		base, err := url.Parse(t)
+		if err != nil || base.Host == "" {
+			continue
+		}
+		for _, san := range hm.Cert.SANs {
+			cand := sanTarget(base, san)
+			if cand == "" {
+				continue
+			}
+			key := originKey(cand)
+			if _, ok := have[key]; ok {
+				continue
+			}
+			// Keyed by origin so the same SAN seen from :443 and the default port is one suggestion.
+			sg := byTarget[key]
+			if sg == nil {
+				sg = &TargetSuggestion{Target: cand}
+				if scope != nil {
+					sg.OutOfScope = scope.Check(cand)
+				}
+				byTarget[key] = sg
+			}
+			sg.From = append(sg.From, t)
+		}
+	}
+
+	out := make([]TargetSuggestion, 0, len(byTarget))
+	for _, sg := range byTarget {
+		sort.Strings(sg.From)
+		out = append(out, *sg)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Target < out[j].Target })
+	return out
+}
+
+// sanTarget builds a target URL for a SAN hostname, or "" for wildcard, IP and malformed names.
+func sanTarget(base *url.URL, san string) string {
+	san = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(san), "."))
+	if san == "" || strings.ContainsAny(san, "*/:@ ") || net.ParseIP(san) != nil {
+		return ""
+	}
+	host := san
+	if p := base.Port(); p != "" {
+		host = net.JoinHostPort(san, p)
+	}
+	return (&url.URL{Scheme: base.Scheme, Host: host}).String()
+}
+
+// originKey is scheme://host:port with the default port filled in, or "" for a bad URL.
+func originKey(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	scheme := strings.ToLower(u.Scheme)
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if scheme == "https" {
+			port = "443"
+		}
+	}
+	return scheme + "://" + net.JoinHostPort(strings.ToLower(u.Hostname()), port)
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestSANTarget(t *testing.T) {
+	tests := []struct {
+		base string
+		san  string
+		want string
+	}{
+		{"https://a.example.com", "b.example.com", "https://b.example.com"},
+		{"https://a.example.com/app/", "B.Example.COM.", "https://b.example.com"},
+		{"https://a.example.com:8443", "b.example.com", "https://b.example.com:8443"},
+		{"http://a.example.com:80", "b.example.com", "http://b.example.com:80"},
+		{"https://a.example.com", " b.example.com ", "https://b.example.com"},
+		{"https://a.example.com", "*.example.com", ""},
+		{"https://a.example.com", "10.0.0.1", ""},
+		{"https://a.example.com", "::1", ""},
+		{"https://a.example.com", "user@b.example.com", ""},
+		{"https://a.example.com", "", ""},
+	}
+	for _, tt := range tests {
+		base, err := url.Parse(tt.base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sanTarget(base, tt.san); got != tt.want {
+			t.Errorf("sanTarget(%s, %q) = %q, want %q", tt.base, tt.san, got, tt.want)
+		}
+	}
+}
+
+func TestOriginKey(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"https://Example.com", "https://example.com:443"},
+		{"https://example.com:443/x", "https://example.com:443"},
+		{"http://example.com", "http://example.com:80"},
+		{"HTTP://example.com:8080/", "http://example.com:8080"},
+		{"https://[2001:db8::1]", "https://[2001:db8::1]:443"},
+		{" https://example.com ", "https://example.com:443"},
+		{"example.com", ""},
+		{"://bad", ""},
+	}
+	for _, tt := range tests {
+		if got := originKey(tt.raw); got != tt.want {
+			t.Errorf("originKey(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestSANSuggestions(t *testing.T) {
+	m := Meta{
+		Targets: []string{"https://a.example.com", "https://c.example.com:443"},
+		Hosts: map[string]HostMeta{
+			"https://a.example.com":     {Cert: &CertInfo{SANs: []string{"a.example.com", "b.example.com", "c.example.com", "*.example.com", "evil.test", "10.0.0.1"}}},
+			"https://c.example.com:443": {Cert: &CertInfo{SANs: []string{"b.example.com"}}},
+			"http://d.example.com":      {},
+		},
+	}
+	scope := &Scope{Include: ScopeRules{Domains: []string{"*.example.com"}}}
+
+	want := []TargetSuggestion{
+		{Target: "https://b.example.com", From: []string{"https://a.example.com", "https://c.example.com:443"}},
+		{Target: "https://evil.test", From: []string{"https://a.example.com"}, OutOfScope: "host not in scope"},
+	}
+	if got := m.SANSuggestions(scope); !reflect.DeepEqual(got, want) {
+		t.Errorf("SANSuggestions = %+v, want %+v", got, want)
+	}
+	// The :443 SAN target of c.example.com collapses with the default-port target.
+	if got := m.SANSuggestions(nil); len(got) != 2 || got[1].OutOfScope != "" {
+		t.Errorf("SANSuggestions(nil) = %+v", got)
+	}
+}
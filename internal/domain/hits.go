@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// HitsFilter selects which stored findings feed the learned "hits" wordlist.
+type HitsFilter struct {
+	Tags           []string `json:"tags,omitempty"`  // scan must carry at least one of these
+	Since          string   `json:"since,omitempty"` // RFC3339 or YYYY-MM-DD, compared with the scan's startedAt
+	Until          string   `json:"until,omitempty"`
+	MinHosts       int      `json:"minHosts,omitempty"` // drop paths seen on fewer distinct hosts
+	IncludeSoft404 bool     `json:"includeSoft404,omitempty"`
+}
+
+// PathHit is one path and the number of distinct hosts it produced a finding on. Probed, when
+// counted, is the number of distinct hosts whose scan wordlist held the path.
+type PathHit struct {
+	Path   string `json:"path"`
+	Hosts  int    `json:"hosts"`
+	Probed int    `json:"probed,omitempty"`
+}
+
+// Rate is the share of probed hosts the path was found on, smoothed as (hits+1)/(probed+2) so a
+// single lucky probe does not outrank a steady performer. Paths from spidering or variants count
+// as probed at least where they were found.
+func (h PathHit) Rate() float64 {
+	n := h.Probed
+	if n < h.Hosts {
+		n = h.Hosts
+	}
+	return float64(h.Hosts+1) / float64(n+2)
+}
+
+// HitScan is a stored scan matching a HitsFilter, with or without findings: the wordlist it ran
+// and the hosts it probed. It is the denominator for a path's hit rate.
+type HitScan struct {
+	WordlistID string
+	Hosts      []string // lowercased targets, as PathHits counts them
+}
+
+type HitsConfig struct {
+	HitsFilter
+	RefreshMinutes int `json:"refreshMinutes"` // 0 disables automatic refresh
+}
+
+// HitsState is the persisted config plus the outcome of the last build.
+type HitsState struct {
+	Config     HitsConfig `json:"config"`
+	WordlistID string     `json:"wordlistId,omitempty"`
+	Entries    int64      `json:"entries"`
+	Scans      int        `json:"scans"`
+	LastRunAt  string     `json:"lastRunAt,omitempty"`
+	LastError  string     `json:"lastError,omitempty"`
+}
+
+func (c *HitsConfig) NormalizeAndValidate() map[string]string {
+	details := map[string]string{}
+	if c == nil {
+		details["config"] = "required"
+		return details
+	}
+
+	c.Tags = trimNonEmpty(c.Tags)
+	c.Since = strings.TrimSpace(c.Since)
+	c.Until = strings.TrimSpace(c.Until)
+
+	if _, ok := ParseHitsTime(c.Since); !ok {
+		details["since"] = "must be RFC3339 or YYYY-MM-DD"
+	}
+	if _, ok := ParseHitsTime(c.Until); !ok {
+		details["until"] = "must be RFC3339 or YYYY-MM-DD"
+	}
+	if c.MinHosts < 0 {
+		details["minHosts"] = "must be >= 0"
+	}
+	if c.RefreshMinutes < 0 {
+		details["refreshMinutes"] = "must be >= 0"
+	} else if c.RefreshMinutes > 0 && c.RefreshMinutes < 5 {
+		details["refreshMinutes"] = "must be 0 or at least 5"
+	}
+
+	return details
+}
+
+// ParseHitsTime accepts "" (no bound), RFC3339 or a bare date.
+func ParseHitsTime(v string) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Host group kinds.
+const (
+	HostGroupFavicon = "favicon"
+	HostGroupTitle   = "title"
+)
+
+// HostGroup is a set of hosts sharing a favicon hash or landing page title.
+type HostGroup struct {
+	Kind    string   `json:"kind"`
+	Value   string   `json:"value"`
+	Targets []string `json:"targets"`
+}
+
+// HostGroups groups the scan's hosts by favicon hash and by landing title, keeping groups of two
+// or more hosts, largest first.
+func (m *Meta) HostGroups() []HostGroup {
+	type key struct{ kind, value string }
+	byKey := map[key][]string{}
+	for t, hm := range m.Hosts {
+		if hm.FaviconHash != nil {
+			k := key{HostGroupFavicon, strconv.FormatInt(int64(*hm.FaviconHash), 10)}
+			byKey[k] = append(byKey[k], t)
+		}
+		if hm.Title != "" {
+			k := key{HostGroupTitle, hm.Title}
+			byKey[k] = append(byKey[k], t)
+		}
+	}
+
+	var out []HostGroup
+	for k, targets := range byKey {
+		if len(targets) < 2 {
+			continue
+		}
+		sort.Strings(targets)
+		out = append(out, HostGroup{Kind: k.kind, Value: k.value, Targets: targets})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].Targets) != len(out[j].Targets) {
+			return len(out[i].Targets) > len(out[j].Targets)
+		}
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
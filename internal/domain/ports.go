@@ -3,10 +3,21 @@ package domain
 import "context"
 
 type WordlistStore interface {
-	WordlistLines(ctx context.Context, wordlistID string) ([]string, error)
+	OpenWordlist(ctx context.Context, wordlistID string) (WordlistSource, error)
 	WordlistMeta(ctx context.Context, wordlistID string) (*WordlistMeta, error)
 }
 
+// WordlistSource is a normalised, deduplicated wordlist that many cursors can read at once.
+type WordlistSource interface {
+	Cursor() PathCursor
+	Close() error
+}
+
+type PathCursor interface {
+	Next() (string, bool)
+	Err() error
+}
+
 type ScanRecorder interface {
 	WriteFinding(f Finding) error
 	WriteProbe(p Probe) error
@@ -22,3 +33,23 @@ type ScanRepo interface {
 type Emitter interface {
 	Emit(event string, payload any)
 }
+
+// HitSource ranks paths by how many distinct hosts they produced findings on in stored scans.
+type HitSource interface {
+	PathHits(f HitsFilter) ([]PathHit, int, error)
+}
+
+// HitRateSource supplies prior hits with their probed counts, as cached by the last learned-list
+// build; nil hits means none has been cached.
+type HitRateSource interface {
+	HitRates() ([]PathHit, error)
+}
+
+type ScopeSource interface {
+	LoadScope(ctx context.Context) (*Scope, error)
+}
+
+// TechRuleSource supplies the custom fingerprint rules used alongside DefaultTechRules.
+type TechRuleSource interface {
+	LoadTechRules(ctx context.Context) ([]TechRule, error)
+}
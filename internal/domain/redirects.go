@@ -0,0 +1,40 @@
+package domain
+
+// MaxFollowRedirects caps StartRequest.FollowRedirects.
+const MaxFollowRedirects = 10
+
+// Redirect classes, from most to least specific.
+const (
+	RedirectLogin         = "login"          // some hop lands on a login/SSO page
+	RedirectTrailingSlash = "trailing-slash" // directory: /x -> /x/
+	RedirectHTTPS         = "https-upgrade"  // same host and path over https
+	RedirectOffHost       = "off-host"       // ends on another host
+	RedirectOther         = "other"
+)
+
+// Reasons a redirect chain was not followed to a non-redirect answer.
+const (
+	RedirectStopMaxHops    = "max-hops"
+	RedirectStopOffHost    = "off-host"
+	RedirectStopOutOfScope = "out-of-scope"
+	RedirectStopLoop       = "loop"
+	RedirectStopError      = "error"
+)
+
+// RedirectHop is one followed Location. Status is 0 for a hop that was not requested.
+type RedirectHop struct {
+	URL    string `json:"url"`
+	Status int    `json:"status,omitempty"`
+	Length int64  `json:"length,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RedirectInfo is the followed chain of a 3xx finding.
+type RedirectInfo struct {
+	Chain       []RedirectHop `json:"chain"`
+	FinalURL    string        `json:"finalUrl"`
+	FinalStatus int           `json:"finalStatus"`
+	FinalLength int64         `json:"finalLength"`
+	Class       string        `json:"class"`
+	Stopped     string        `json:"stopped,omitempty"` // why following ended early
+}
@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ScopeRules is one side (include or exclude) of a scope configuration.
+// Domains accepts exact host names and "*.example.com" wildcards (which also match the apex).
+type ScopeRules struct {
+	Domains []string `json:"domains,omitempty"`
+	CIDRs   []string `json:"cidrs,omitempty"`
+	Ports   []int    `json:"ports,omitempty"`
+}
+
+type Scope struct {
+	Include   ScopeRules `json:"include"`
+	Exclude   ScopeRules `json:"exclude"`
+	UpdatedAt string     `json:"updatedAt,omitempty"`
+}
+
+func (r *ScopeRules) normalize() {
+	out := make([]string, 0, len(r.Domains))
+	for _, d := range r.Domains {
+		d = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			out = append(out, d)
+		}
+	}
+	r.Domains = nil
+	if len(out) > 0 {
+		r.Domains = out
+	}
+	r.CIDRs = trimNonEmpty(r.CIDRs)
+}
+
+func (r *ScopeRules) validate(prefix string, details map[string]string) {
+	for _, d := range r.Domains {
+		host := strings.TrimPrefix(d, "*.")
+		if host == "" || strings.ContainsAny(host, "*/:@ ") {
+			details[prefix+".domains"] = "invalid domain: " + d
+			break
+		}
+	}
+	for _, c := range r.CIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil && net.ParseIP(c) == nil {
+			details[prefix+".cidrs"] = "invalid CIDR: " + c
+			break
+		}
+	}
+	for _, p := range r.Ports {
+		if p < 1 || p > 65535 {
+			details[prefix+".ports"] = "ports must be 1-65535"
+			break
+		}
+	}
+}
+
+func (r *ScopeRules) hasHostRules() bool { return len(r.Domains) > 0 || len(r.CIDRs) > 0 }
+
+func (r *ScopeRules) matchIP(ip net.IP) bool {
+	for _, c := range r.CIDRs {
+		if _, n, err := net.ParseCIDR(c); err == nil {
+			if n.Contains(ip) {
+				return true
+			}
+		} else if single := net.ParseIP(c); single != nil && single.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ScopeRules) matchHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ip := net.ParseIP(host); ip != nil && r.matchIP(ip) {
+		return true
+	}
+	for _, d := range r.Domains {
+		if strings.HasPrefix(d, "*.") {
+			apex := d[2:]
+			if host == apex || strings.HasSuffix(host, "."+apex) {
+				return true
+			}
+			continue
+		}
+		if host == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ScopeRules) matchPort(port int) bool {
+	for _, p := range r.Ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scope) NormalizeAndValidate() map[string]string {
+	details := map[string]string{}
+	if s == nil {
+		details["scope"] = "required"
+		return details
+	}
+	s.Include.normalize()
+	s.Exclude.normalize()
+	s.Include.validate("include", details)
+	s.Exclude.validate("exclude", details)
+	return details
+}
+
+// IsEmpty reports whether the scope places no restriction on targets.
+func (s *Scope) IsEmpty() bool {
+	if s == nil {
+		return true
+	}
+	return !s.Include.hasHostRules() && len(s.Include.Ports) == 0 &&
+		!s.Exclude.hasHostRules() && len(s.Exclude.Ports) == 0
+}
+
+// Check returns "" when rawURL is in scope, otherwise a short reason.
+// Hostnames are only matched against domain rules; CIDRs apply to IP-literal hosts, via CheckIP
+// to the addresses hostnames are pinned to, and via ExcludesIP to the addresses they resolve to.
+func (s *Scope) Check(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "invalid URL"
+	}
+	if s.IsEmpty() {
+		return ""
+	}
+
+	host := u.Hostname()
+	port := URLPort(u)
+
+	if s.Exclude.matchHost(host) {
+		return "host is excluded"
+	}
+	if s.Exclude.matchPort(port) {
+		return "port " + strconv.Itoa(port) + " is excluded"
+	}
+	if s.Include.hasHostRules() && !s.Include.matchHost(host) {
+		return "host not in scope"
+	}
+	if len(s.Include.Ports) > 0 && !s.Include.matchPort(port) {
+		return "port " + strconv.Itoa(port) + " not in scope"
+	}
+	return ""
+}
+
+// CheckIP returns "" when ip passes the CIDR rules, otherwise a short reason. It is used for
+// addresses a hostname is pinned to: an excluded CIDR rejects the IP, and when the include side
+// lists CIDRs the IP must fall in one of them.
+func (s *Scope) CheckIP(ip string) string {
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return "invalid IP"
+	}
+	if s.IsEmpty() {
+		return ""
+	}
+	if s.Exclude.matchIP(addr) {
+		return "IP is excluded"
+	}
+	if len(s.Include.CIDRs) > 0 && !s.Include.matchIP(addr) {
+		return "IP not in scope"
+	}
+	return ""
+}
+
+// ExcludesIP reports whether ip falls in an excluded CIDR. It is checked on every address the
+// scanner connects to, so a hostname that resolves into an excluded range is not probed; the
+// include CIDRs are left out as in-scope hostnames were already matched by domain rules.
+func (s *Scope) ExcludesIP(ip net.IP) bool {
+	return s != nil && ip != nil && s.Exclude.matchIP(ip)
+}
+
+// URLPort returns the explicit port of u, or the scheme default.
+func URLPort(u *url.URL) int {
+	if u == nil {
+		return 0
+	}
+	if p := u.Port(); p != "" {
+		n, _ := strconv.Atoi(p)
+		return n
+	}
+	if strings.EqualFold(u.Scheme, "https") {
+		return 443
+	}
+	return 80
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"net"
+	"testing"
+)
+
+func TestScopeCheck(t *testing.T) {
+	sc := Scope{
+		Include: ScopeRules{
+			Domains: []string{"*.example.com", "api.test"},
+			CIDRs:   []string{"10.0.0.0/8", "192.168.1.5"},
+			Ports:   []int{80, 443, 8443},
+		},
+		Exclude: ScopeRules{
+			Domains: []string{"admin.example.com"},
+			CIDRs:   []string{"10.9.0.0/16"},
+			Ports:   []int{8443},
+		},
+	}
+	if details := sc.NormalizeAndValidate(); len(details) > 0 {
+		t.Fatalf("NormalizeAndValidate: %v", details)
+	}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.example.com", ""},
+		{"https://deep.sub.example.com/x", ""},
+		{"http://example.com", ""}, // the wildcard also matches the apex
+		{"http://WWW.Example.COM.", ""},
+		{"http://badexample.com", "host not in scope"},
+		{"http://example.com.evil.net", "host not in scope"},
+		{"http://api.test", ""},
+		{"http://www.api.test", "host not in scope"},
+		{"http://admin.example.com", "host is excluded"},
+		{"http://10.1.2.3", ""},
+		{"http://192.168.1.5", ""},
+		{"http://192.168.1.6", "host not in scope"},
+		{"http://10.9.1.1", "host is excluded"},
+		{"https://www.example.com:8443", "port 8443 is excluded"},
+		{"http://www.example.com:8080", "port 8080 not in scope"},
+		{"http://[::1]", "host not in scope"},
+		{"www.example.com", "invalid URL"},
+		{"://", "invalid URL"},
+	}
+	for _, tt := range tests {
+		if got := sc.Check(tt.url); got != tt.want {
+			t.Errorf("Check(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestScopeCheckEmpty(t *testing.T) {
+	var sc Scope
+	for _, u := range []string{"http://anything.example", "https://10.0.0.1:9999"} {
+		if got := sc.Check(u); got != "" {
+			t.Errorf("Check(%q) = %q, want in scope", u, got)
+		}
+	}
+	if got := sc.Check("not a url"); got != "invalid URL" {
+		t.Errorf("Check(invalid) = %q", got)
+	}
+}
+
+func TestScopeCheckPortsOnly(t *testing.T) {
+	sc := Scope{Include: ScopeRules{Ports: []int{443}}}
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://a.example", ""},
+		{"http://a.example", "port 80 not in scope"},
+		{"http://a.example:443", ""},
+	}
+	for _, tt := range tests {
+		if got := sc.Check(tt.url); got != tt.want {
+			t.Errorf("Check(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestScopeCheckIP(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope Scope
+		ip    string
+		want  string
+	}{
+		{"empty scope", Scope{}, "10.0.0.1", ""},
+		{"invalid", Scope{}, "10.0.0", "invalid IP"},
+		{"excluded", Scope{Exclude: ScopeRules{CIDRs: []string{"10.0.0.0/8"}}}, "10.0.0.5", "IP is excluded"},
+		{"excluded single", Scope{Exclude: ScopeRules{CIDRs: []string{"10.0.0.5"}}}, "10.0.0.5", "IP is excluded"},
+		{"included", Scope{Include: ScopeRules{CIDRs: []string{"10.0.0.0/8"}}}, "10.2.3.4", ""},
+		{"not included", Scope{Include: ScopeRules{CIDRs: []string{"10.0.0.0/8"}}}, "11.0.0.1", "IP not in scope"},
+		{"domains only", Scope{Include: ScopeRules{Domains: []string{"example.com"}}}, "11.0.0.1", ""},
+		{"ipv6", Scope{Include: ScopeRules{CIDRs: []string{"2001:db8::/32"}}}, "2001:db8::1", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.scope.CheckIP(tt.ip); got != tt.want {
+			t.Errorf("%s: CheckIP(%q) = %q, want %q", tt.name, tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestScopeExcludesIP(t *testing.T) {
+	s := &Scope{
+		Include: ScopeRules{Domains: []string{"*.example.com"}, CIDRs: []string{"192.0.2.0/24"}},
+		Exclude: ScopeRules{CIDRs: []string{"10.0.0.0/8", "2001:db8::/32"}},
+	}
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"2001:db8::1", true},
+		{"203.0.113.9", false}, // outside the include CIDRs, but hostnames are matched by domain
+		{"192.0.2.1", false},
+	}
+	for _, tt := range tests {
+		if got := s.ExcludesIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("ExcludesIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+	if (*Scope)(nil).ExcludesIP(net.ParseIP("10.0.0.1")) {
+		t.Error("nil scope excludes an IP")
+	}
+}
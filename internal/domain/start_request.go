@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"net"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -48,6 +51,10 @@ func (r *StartRequest) NormalizeAndValidate() map[string]string {
 		details["rateLimit"] = "must be >= 0"
 	}
 
+	if r.HostRateLimit < 0 {
+		details["hostRateLimit"] = "must be >= 0"
+	}
+
 	if tagsProvided && len(r.Tags) == 0 {
 		details["tags"] = "must contain at least one non-empty tag"
 	}
@@ -61,6 +68,124 @@ func (r *StartRequest) NormalizeAndValidate() map[string]string {
 		}
 	}
 
+	if len(r.Resolve) > 0 {
+		hosts := make([]string, 0, len(r.Resolve))
+		for host := range r.Resolve {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+
+		resolve := make(map[string]string, len(r.Resolve))
+		var bad []string
+		for _, raw := range hosts {
+			host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(raw)), ".")
+			ip := strings.TrimSpace(r.Resolve[raw])
+			if host == "" || strings.ContainsAny(host, "/:@ ") {
+				bad = append(bad, "invalid host name: "+raw)
+				continue
+			}
+			if net.ParseIP(ip) == nil {
+				bad = append(bad, "invalid IP for "+host+": "+ip)
+				continue
+			}
+			resolve[host] = ip
+		}
+		if len(bad) > 0 {
+			details["resolve"] = strings.Join(bad, "; ")
+		}
+		r.Resolve = resolve
+	}
+
+	r.DNSServer = strings.TrimSpace(r.DNSServer)
+	if r.DNSServer != "" {
+		if addr, ok := normalizeDNSServer(r.DNSServer); ok {
+			r.DNSServer = addr
+		} else {
+			details["dnsServer"] = "must be an IP or IP:port (e.g. 10.0.0.53 or 10.0.0.53:5353)"
+		}
+	}
+
+	r.SourceIP = strings.TrimSpace(r.SourceIP)
+	if r.SourceIP != "" {
+		if ip := net.ParseIP(r.SourceIP); ip == nil || ip.To4() == nil {
+			details["sourceIP"] = "must be a local IPv4 address"
+		} else {
+			r.SourceIP = ip.To4().String()
+		}
+	}
+
+	r.LengthMode = strings.ToLower(strings.TrimSpace(r.LengthMode))
+	switch r.LengthMode {
+	case "":
+		r.LengthMode = LengthModeBody
+	case LengthModeBody, LengthModeDecoded, LengthModeFull:
+	default:
+		details["lengthMode"] = `must be "body", "decoded" or "full"`
+	}
+
+	if r.MaxBodyBytes < 0 {
+		details["maxBodyBytes"] = "must be >= 0"
+	}
+
+	if r.FollowRedirects < 0 || r.FollowRedirects > MaxFollowRedirects {
+		details["followRedirects"] = "must be 0-" + strconv.Itoa(MaxFollowRedirects)
+	}
+	if r.FollowRedirects == 0 {
+		r.RedirectCrossHost = false
+	}
+
+	r.normalizeVariantPatterns(details)
+	r.normalizeCaptureHeaders(details)
+	r.normalizeTransport(details)
+
+	return details
+}
+
+func normalizeDNSServer(s string) (string, bool) {
+	if ip := net.ParseIP(strings.Trim(s, "[]")); ip != nil {
+		return net.JoinHostPort(ip.String(), "53"), true
+	}
+	host, port, err := net.SplitHostPort(s)
+	if err != nil || net.ParseIP(host) == nil {
+		return "", false
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", false
+	}
+	return net.JoinHostPort(host, port), true
+}
+
+// CheckScope reports targets and pinned IPs that fall outside sc, keyed like NormalizeAndValidate.
+// A pin sends a host's traffic to its IP, so the IP must pass the CIDR rules as well.
+func (r *StartRequest) CheckScope(sc *Scope) map[string]string {
+	details := map[string]string{}
+	if r == nil || sc.IsEmpty() {
+		return details
+	}
+	var bad []string
+	for _, t := range r.Targets {
+		if reason := sc.Check(t); reason != "" {
+			bad = append(bad, t+" ("+reason+")")
+		}
+	}
+	if len(bad) > 0 {
+		details["targets"] = "out of scope: " + strings.Join(bad, ", ")
+	}
+
+	hosts := make([]string, 0, len(r.Resolve))
+	for host := range r.Resolve {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	var badPins []string
+	for _, host := range hosts {
+		if reason := sc.CheckIP(r.Resolve[host]); reason != "" {
+			badPins = append(badPins, host+" -> "+r.Resolve[host]+" ("+reason+")")
+		}
+	}
+	if len(badPins) > 0 {
+		details["resolve"] = "out of scope: " + strings.Join(badPins, ", ")
+	}
 	return details
 }
 
@@ -15,6 +15,7 @@ const (
 	HostStatusRunning   HostStatus = "running"
 	HostStatusPaused    HostStatus = "paused"
 	HostStatusStopped   HostStatus = "stopped"
+	HostStatusSkipped   HostStatus = "skipped"
 	HostStatusCompleted HostStatus = "completed"
 	HostStatusError     HostStatus = "error"
 )
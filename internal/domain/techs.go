@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"net/textproto"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultCaptureHeaders are recorded on findings when a scan does not list its own.
+// Set-Cookie is reduced to cookie names.
+var DefaultCaptureHeaders = []string{
+	"Server",
+	"X-Powered-By",
+	"X-AspNet-Version",
+	"X-Generator",
+	"Set-Cookie",
+	"WWW-Authenticate",
+	"Via",
+}
+
+const (
+	maxCaptureHeaders = 50
+	maxTechRules      = 500
+)
+
+// TechRule tags a response with a technology when any of its matchers hits. Header and cookie
+// patterns are regexes on the value; "" only requires the header or cookie to be present.
+// Body patterns run on the first 64 KiB of the (decoded) body.
+type TechRule struct {
+	Name    string            `json:"name"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Cookies map[string]string `json:"cookies,omitempty"`
+	Body    []string          `json:"body,omitempty"`
+}
+
+// TechRuleSet is the persisted set of custom rules, used alongside DefaultTechRules.
+type TechRuleSet struct {
+	Rules     []TechRule `json:"rules"`
+	UpdatedAt string     `json:"updatedAt,omitempty"`
+}
+
+// HostTechsMsg is emitted when a host's detected technologies grow.
+type HostTechsMsg struct {
+	ScanID string   `json:"scanId"`
+	Target string   `json:"target"`
+	Techs  []string `json:"techs"`
+}
+
+// DefaultTechRules is the built-in fingerprint set.
+var DefaultTechRules = []TechRule{
+	{Name: "nginx", Headers: map[string]string{"Server": `(?i)nginx`}},
+	{Name: "Apache", Headers: map[string]string{"Server": `(?i)apache`}},
+	{Name: "IIS", Headers: map[string]string{"Server": `(?i)microsoft-iis`}},
+	{Name: "LiteSpeed", Headers: map[string]string{"Server": `(?i)litespeed`}},
+	{Name: "Caddy", Headers: map[string]string{"Server": `(?i)caddy`}},
+	{Name: "Envoy", Headers: map[string]string{"Server": `(?i)envoy`, "X-Envoy-Upstream-Service-Time": ""}},
+	{Name: "Cloudflare", Headers: map[string]string{"Server": `(?i)cloudflare`, "Cf-Ray": ""}},
+	{Name: "Varnish", Headers: map[string]string{"Via": `(?i)varnish`, "X-Varnish": ""}},
+	{Name: "Amazon CloudFront", Headers: map[string]string{"Via": `(?i)cloudfront`, "X-Amz-Cf-Id": ""}},
+	{Name: "Akamai", Headers: map[string]string{"Server": `(?i)akamai`}},
+	{Name: "PHP", Headers: map[string]string{"X-Powered-By": `(?i)php`}, Cookies: map[string]string{"PHPSESSID": ""}},
+	{Name: "ASP.NET", Headers: map[string]string{"X-Powered-By": `(?i)asp\.net`, "X-AspNet-Version": ""}, Cookies: map[string]string{"ASP.NET_SessionId": "", ".ASPXAUTH": ""}},
+	{Name: "Java", Cookies: map[string]string{"JSESSIONID": ""}},
+	{Name: "Apache Tomcat", Headers: map[string]string{"Server": `(?i)tomcat|coyote`}, Body: []string{`Apache Tomcat/\d`}},
+	{Name: "Spring Boot", Body: []string{`Whitelabel Error Page`}},
+	{Name: "Express", Headers: map[string]string{"X-Powered-By": `(?i)^express`}, Cookies: map[string]string{"connect.sid": ""}},
+	{Name: "Next.js", Headers: map[string]string{"X-Powered-By": `(?i)next\.js`}, Body: []string{`__NEXT_DATA__`}},
+	{Name: "Laravel", Cookies: map[string]string{"laravel_session": ""}},
+	{Name: "Django", Cookies: map[string]string{"csrftoken": "", "django_language": ""}},
+	{Name: "Ruby on Rails", Headers: map[string]string{"X-Runtime": `^[\d.]+$`}, Cookies: map[string]string{"_rails_session": ""}},
+	{Name: "Flask", Headers: map[string]string{"Server": `(?i)werkzeug`}},
+	{Name: "WordPress", Headers: map[string]string{"X-Generator": `(?i)wordpress`}, Body: []string{`/wp-(content|includes)/`}},
+	{Name: "Drupal", Headers: map[string]string{"X-Generator": `(?i)drupal`, "X-Drupal-Cache": ""}, Body: []string{`Drupal\.settings|drupal-settings-json`}},
+	{Name: "Joomla", Body: []string{`(?i)<meta name="generator" content="Joomla`}},
+	{Name: "Jenkins", Headers: map[string]string{"X-Jenkins": ""}},
+	{Name: "GitLab", Cookies: map[string]string{"_gitlab_session": ""}},
+	{Name: "Grafana", Body: []string{`grafana-app|window\.grafanaBootData`}},
+	{Name: "Kibana", Headers: map[string]string{"Kbn-Name": ""}},
+	{Name: "phpMyAdmin", Cookies: map[string]string{"phpMyAdmin": "", "pma_lang": ""}},
+	{Name: "Basic auth", Headers: map[string]string{"Www-Authenticate": `(?i)^basic`}},
+	{Name: "NTLM auth", Headers: map[string]string{"Www-Authenticate": `(?i)^(ntlm|negotiate)`}},
+}
+
+// normalizeCaptureHeaders canonicalises and dedupes header names, defaulting to DefaultCaptureHeaders.
+func (r *StartRequest) normalizeCaptureHeaders(details map[string]string) {
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(r.CaptureHeaders))
+	for _, h := range trimNonEmpty(r.CaptureHeaders) {
+		if strings.ContainsAny(h, " :\r\n") {
+			details["captureHeaders"] = "invalid header name: " + h
+			return
+		}
+		h = textproto.CanonicalMIMEHeaderKey(h)
+		if _, dup := seen[h]; dup {
+			continue
+		}
+		seen[h] = struct{}{}
+		out = append(out, h)
+	}
+	if len(out) > maxCaptureHeaders {
+		details["captureHeaders"] = "at most " + strconv.Itoa(maxCaptureHeaders) + " headers"
+		return
+	}
+	if len(out) == 0 {
+		out = append(out, DefaultCaptureHeaders...)
+	}
+	r.CaptureHeaders = out
+}
+
+func (s *TechRuleSet) NormalizeAndValidate() map[string]string {
+	details := map[string]string{}
+	if s == nil {
+		details["rules"] = "required"
+		return details
+	}
+	if len(s.Rules) > maxTechRules {
+		details["rules"] = "at most " + strconv.Itoa(maxTechRules) + " rules"
+		return details
+	}
+	for i := range s.Rules {
+		if msg := s.Rules[i].normalize(); msg != "" {
+			details["rules["+strconv.Itoa(i)+"]"] = msg
+		}
+	}
+	return details
+}
+
+// normalize trims and canonicalises the rule and returns a problem description, or "".
+func (t *TechRule) normalize() string {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return "name is required"
+	}
+
+	headers := make(map[string]string, len(t.Headers))
+	for k, v := range t.Headers {
+		k = strings.TrimSpace(k)
+		if k == "" || strings.ContainsAny(k, " :\r\n") {
+			return "invalid header name: " + k
+		}
+		if _, err := regexp.Compile(v); err != nil {
+			return "invalid header pattern for " + k + ": " + err.Error()
+		}
+		headers[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+	t.Headers = nil
+	if len(headers) > 0 {
+		t.Headers = headers
+	}
+
+	for k, v := range t.Cookies {
+		if strings.TrimSpace(k) == "" {
+			return "cookie name is required"
+		}
+		if _, err := regexp.Compile(v); err != nil {
+			return "invalid cookie pattern for " + k + ": " + err.Error()
+		}
+	}
+	if len(t.Cookies) == 0 {
+		t.Cookies = nil
+	}
+
+	t.Body = trimNonEmpty(t.Body)
+	for _, b := range t.Body {
+		if _, err := regexp.Compile(b); err != nil {
+			return "invalid body pattern: " + err.Error()
+		}
+	}
+
+	if len(t.Headers) == 0 && len(t.Cookies) == 0 && len(t.Body) == 0 {
+		return "needs at least one header, cookie or body pattern"
+	}
+	return ""
+}
+
+// MergeTechs returns the sorted union of set and techs and whether it grew. set is not modified.
+func MergeTechs(set []string, techs []string) ([]string, bool) {
+	var out []string
+	for _, t := range techs {
+		cur := set
+		if out != nil {
+			cur = out
+		}
+		i := sort.SearchStrings(cur, t)
+		if i < len(cur) && cur[i] == t {
+			continue
+		}
+		next := make([]string, 0, len(cur)+1)
+		next = append(next, cur[:i]...)
+		next = append(next, t)
+		out = append(next, cur[i:]...)
+	}
+	if out == nil {
+		return set, false
+	}
+	return out, true
+}
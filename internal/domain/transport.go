@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+)
+
+// HTTP versions a scan can pin its transport to; "" negotiates (HTTP/2 over TLS when offered).
+const (
+	HTTPVersion11 = "1.1"
+	HTTPVersion2  = "2"
+)
+
+// TransportOptions tunes the scan's HTTP transport. Zero values keep the defaults.
+type TransportOptions struct {
+	// HTTPVersion is "", "1.1" or "2". HTTP/2 is only available over TLS (no h2c) and without a proxy.
+	HTTPVersion      string `json:"httpVersion,omitempty"`
+	DisableKeepAlive bool   `json:"disableKeepAlive,omitempty"`
+	MaxConnsPerHost  int    `json:"maxConnsPerHost,omitempty"`
+	DialTimeoutMs    int    `json:"dialTimeoutMs,omitempty"`
+	TLSTimeoutMs     int    `json:"tlsTimeoutMs,omitempty"`
+}
+
+// IsZero reports whether every option is left at its default.
+func (t TransportOptions) IsZero() bool { return t == TransportOptions{} }
+
+func (r *StartRequest) normalizeTransport(details map[string]string) {
+	t := &r.Transport
+	t.HTTPVersion = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(t.HTTPVersion)), "HTTP/")
+	switch t.HTTPVersion {
+	case "", HTTPVersion11:
+	case HTTPVersion2, "2.0":
+		t.HTTPVersion = HTTPVersion2
+		if r.RawHTTP {
+			details["transport.httpVersion"] = "raw HTTP mode only speaks HTTP/1.1"
+		}
+		if r.Proxy != "" {
+			details["transport.httpVersion"] = "proxied requests only speak HTTP/1.1"
+		}
+		for _, raw := range r.Targets {
+			if u, err := url.Parse(raw); err == nil && !strings.EqualFold(u.Scheme, "https") {
+				details["transport.httpVersion"] = "HTTP/2 needs https targets: " + raw
+				break
+			}
+		}
+	default:
+		details["transport.httpVersion"] = `must be "1.1" or "2"`
+	}
+
+	if t.MaxConnsPerHost < 0 || t.MaxConnsPerHost > 1024 {
+		details["transport.maxConnsPerHost"] = "must be 0-1024 (0 = default)"
+	}
+	if t.DialTimeoutMs < 0 || t.DialTimeoutMs > 120000 {
+		details["transport.dialTimeoutMs"] = "must be 0-120000 (0 = default)"
+	}
+	if t.TLSTimeoutMs < 0 || t.TLSTimeoutMs > 120000 {
+		details["transport.tlsTimeoutMs"] = "must be 0-120000 (0 = default)"
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+func TestNormalizeTransportHTTPVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     StartRequest
+		want    string
+		wantErr bool
+	}{
+		{"negotiate", StartRequest{Targets: []string{"http://a.test"}}, "", false},
+		{"1.1", StartRequest{Transport: TransportOptions{HTTPVersion: "HTTP/1.1"}}, HTTPVersion11, false},
+		{"2", StartRequest{Targets: []string{"https://a.test"}, Transport: TransportOptions{HTTPVersion: "http/2.0"}}, HTTPVersion2, false},
+		{"2 over http", StartRequest{Targets: []string{"http://a.test"}, Transport: TransportOptions{HTTPVersion: "2"}}, HTTPVersion2, true},
+		{"2 raw", StartRequest{RawHTTP: true, Transport: TransportOptions{HTTPVersion: "2"}}, HTTPVersion2, true},
+		{"2 proxied", StartRequest{Targets: []string{"https://a.test"}, Proxy: "http://127.0.0.1:8080", Transport: TransportOptions{HTTPVersion: "2"}}, HTTPVersion2, true},
+		{"1.1 proxied", StartRequest{Proxy: "http://127.0.0.1:8080", Transport: TransportOptions{HTTPVersion: "1.1"}}, HTTPVersion11, false},
+		{"3", StartRequest{Transport: TransportOptions{HTTPVersion: "3"}}, "3", true},
+	}
+	for _, tt := range tests {
+		details := map[string]string{}
+		tt.req.normalizeTransport(details)
+		_, gotErr := details["transport.httpVersion"]
+		if tt.req.Transport.HTTPVersion != tt.want || gotErr != tt.wantErr {
+			t.Errorf("%s: version %q, error %q; want %q, error %v",
+				tt.name, tt.req.Transport.HTTPVersion, details["transport.httpVersion"], tt.want, tt.wantErr)
+		}
+	}
+}
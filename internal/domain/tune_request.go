@@ -0,0 +1,34 @@
+package domain
+
+import "strings"
+
+func (r *TuneRequest) NormalizeAndValidate() map[string]string {
+	details := map[string]string{}
+	if r == nil {
+		details["request"] = "required"
+		return details
+	}
+
+	r.ScanID = strings.TrimSpace(r.ScanID)
+	if !IsValidScanID(r.ScanID) {
+		details["scanId"] = "must be a 32-char lowercase hex id"
+	}
+
+	if r.Concurrency == nil && r.RateLimit == nil && r.HostRateLimit == nil && r.TimeoutMs == nil {
+		details["request"] = "at least one setting is required"
+	}
+	if r.Concurrency != nil && *r.Concurrency <= 0 {
+		details["concurrency"] = "must be > 0"
+	}
+	if r.RateLimit != nil && *r.RateLimit < 0 {
+		details["rateLimit"] = "must be >= 0"
+	}
+	if r.HostRateLimit != nil && *r.HostRateLimit < 0 {
+		details["hostRateLimit"] = "must be >= 0"
+	}
+	if r.TimeoutMs != nil && *r.TimeoutMs <= 0 {
+		details["timeoutMs"] = "must be > 0"
+	}
+
+	return details
+}
@@ -4,21 +4,106 @@ type WordlistMeta struct {
 	ID         string   `json:"id"`
 	Names      []string `json:"names"`
 	Bytes      int64    `json:"bytes"`
+	Entries    int64    `json:"entries"` // unique normalised paths
 	UploadedAt string   `json:"uploadedAt"`
+	SourcePath string   `json:"sourcePath,omitempty"` // set when registered by reference, not copied
+
+	// SourceSize and SourceModTime describe the referenced file when it was hashed. Stale is set
+	// once it no longer matches them, so the file's content is no longer what the ID names.
+	SourceSize    int64  `json:"sourceSize,omitempty"`
+	SourceModTime string `json:"sourceModTime,omitempty"`
+	Stale         bool   `json:"stale,omitempty"`
+
+	// User-editable; DisplayName wins over Names[0] when set.
+	DisplayName string   `json:"displayName,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// Lineage records how a derived list was built from other lists.
+	Lineage *WordlistLineage `json:"lineage,omitempty"`
+	Stats   *WordlistStats   `json:"stats,omitempty"`
+}
+
+// WordlistStats is computed once, when the normalised sidecar is built.
+type WordlistStats struct {
+	Lines      int64            `json:"lines"`      // non-empty source lines, before dedupe
+	Extensions []ExtensionCount `json:"extensions"` // most common first; "" counts entries without one
+	Depth      []int64          `json:"depth"`      // Depth[n] = entries with n path segments; the last bucket is "n or more"
+}
+
+type ExtensionCount struct {
+	Ext   string `json:"ext"`
+	Count int64  `json:"count"`
 }
 
 type StartRequest struct {
-	ScanID      string   `json:"scanId,omitempty"`
-	Targets     []string `json:"targets"`
-	WordlistID  string   `json:"wordlistId"`
-	Concurrency int      `json:"concurrency"`
-	TimeoutMs   int      `json:"timeoutMs"`
-	RateLimit   int      `json:"rateLimit"` // 0 = unlimited
-	Tags        []string `json:"tags,omitempty"`
-	Verbose     bool     `json:"verbose"`
-	Proxy       string   `json:"proxy,omitempty"`
+	ScanID        string   `json:"scanId,omitempty"`
+	Targets       []string `json:"targets"`
+	WordlistID    string   `json:"wordlistId"`
+	Concurrency   int      `json:"concurrency"`
+	TimeoutMs     int      `json:"timeoutMs"`
+	RateLimit     int      `json:"rateLimit"`               // 0 = unlimited
+	HostRateLimit int      `json:"hostRateLimit,omitempty"` // per target; 0 = unlimited
+	Tags          []string `json:"tags,omitempty"`
+	Verbose       bool     `json:"verbose"`
+	Proxy         string   `json:"proxy,omitempty"`
+
+	// Resolve pins host names to IPs (like curl --resolve); DNSServer is "ip" or "ip:port".
+	Resolve   map[string]string `json:"resolve,omitempty"`
+	DNSServer string            `json:"dnsServer,omitempty"`
+
+	// SourceIP binds outgoing connections to one local IPv4 (multi-homed hosts).
+	SourceIP string `json:"sourceIP,omitempty"`
+
+	// PrioritizeHits probes paths that produced findings in earlier scans first.
+	PrioritizeHits bool `json:"prioritizeHits,omitempty"`
+
+	// Spider harvests paths and words from robots.txt, sitemaps, the landing page and its scripts
+	// before the wordlist phase.
+	Spider bool `json:"spider,omitempty"`
+
+	// Variants probes backup/editor copies of file-like findings; VariantPatterns defaults to
+	// DefaultVariantPatterns.
+	Variants        bool     `json:"variants,omitempty"`
+	VariantPatterns []string `json:"variantPatterns,omitempty"`
+
+	// Bypass retries 401/403 findings with path, header and method mutations.
+	Bypass bool `json:"bypass,omitempty"`
+
+	// RawHTTP sends each path byte for byte as the HTTP/1.1 request target, bypassing URL
+	// parsing and normalisation.
+	RawHTTP bool `json:"rawHttp,omitempty"`
+
+	Transport TransportOptions `json:"transport,omitempty"`
+
+	// LengthMode picks what a probe's length counts (LengthModeBody by default). Compression
+	// accepts gzip/deflate responses; they are decoded when LengthMode is LengthModeDecoded.
+	LengthMode  string `json:"lengthMode,omitempty"`
+	Compression bool   `json:"compression,omitempty"`
+
+	// MaxBodyBytes stops reading a body after that many bytes (0 = no cap); the length then
+	// comes from Content-Length when the server sent one. HeadFirst sends HEAD and only follows
+	// up with GET when the answer is neither a 404 nor a soft-404 match.
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+	HeadFirst    bool  `json:"headFirst,omitempty"`
+
+	// FollowRedirects follows up to that many hops of a 3xx finding (0 = off). Hops stay in
+	// scope and, unless RedirectCrossHost is set, on the finding's host.
+	FollowRedirects   int  `json:"followRedirects,omitempty"`
+	RedirectCrossHost bool `json:"redirectCrossHost,omitempty"`
+
+	// CaptureHeaders are the response headers recorded on findings; DefaultCaptureHeaders when empty.
+	CaptureHeaders []string `json:"captureHeaders,omitempty"`
 }
 
+// Length modes: body bytes as received, body bytes after gzip/deflate decoding, or the whole
+// response including status line and headers.
+const (
+	LengthModeBody    = "body"
+	LengthModeDecoded = "decoded"
+	LengthModeFull    = "full"
+)
+
 type Meta struct {
 	ID            string              `json:"id"`
 	StartedAt     string              `json:"startedAt"`
@@ -30,15 +115,62 @@ type Meta struct {
 	Concurrency   int                 `json:"concurrency"`
 	TimeoutMs     int                 `json:"timeoutMs"`
 	RateLimit     int                 `json:"rateLimit"`
+	HostRateLimit int                 `json:"hostRateLimit,omitempty"`
+	Adjustments   []ScanAdjustment    `json:"adjustments,omitempty"`
 	Tags          []string            `json:"tags,omitempty"`
 	Verbose       bool                `json:"verbose"`
 	LogFile       string              `json:"logFile,omitempty"`
 	Proxy         string              `json:"proxy,omitempty"`
+	Resolve       map[string]string   `json:"resolve,omitempty"`
+	DNSServer     string              `json:"dnsServer,omitempty"`
+	SourceIP      string              `json:"sourceIP,omitempty"`
 	TotalRequests int64               `json:"totalRequests"`
 	TotalFindings int64               `json:"totalFindings"`
 	TotalErrors   int64               `json:"totalErrors"`
 	Hosts         map[string]HostMeta `json:"hosts,omitempty"`
 	Status        ScanStatus          `json:"status,omitempty"`
+
+	PrioritizeHits   bool `json:"prioritizeHits,omitempty"`
+	PrioritizedPaths int  `json:"prioritizedPaths,omitempty"` // wordlist entries moved to the front
+	Spider           bool `json:"spider,omitempty"`
+
+	VariantPatterns []string `json:"variantPatterns,omitempty"`
+	VariantProbes   int64    `json:"variantProbes,omitempty"` // variant probes queued from findings
+
+	Bypass       bool  `json:"bypass,omitempty"`
+	BypassProbes int64 `json:"bypassProbes,omitempty"` // bypass attempts queued from 401/403 findings
+	RawHTTP      bool  `json:"rawHttp,omitempty"`
+
+	Transport *TransportOptions `json:"transport,omitempty"` // nil when every option is left at its default
+
+	LengthMode  string `json:"lengthMode,omitempty"` // what Length counts in this scan's findings and probes
+	Compression bool   `json:"compression,omitempty"`
+
+	MaxBodyBytes int64 `json:"maxBodyBytes,omitempty"`
+	HeadFirst    bool  `json:"headFirst,omitempty"`
+
+	FollowRedirects   int  `json:"followRedirects,omitempty"`
+	RedirectCrossHost bool `json:"redirectCrossHost,omitempty"`
+
+	CaptureHeaders []string `json:"captureHeaders,omitempty"`
+}
+
+// ScanTuning holds the settings that can change on a running scan; nil fields are left as is.
+type ScanTuning struct {
+	Concurrency   *int `json:"concurrency,omitempty"`
+	RateLimit     *int `json:"rateLimit,omitempty"`
+	HostRateLimit *int `json:"hostRateLimit,omitempty"`
+	TimeoutMs     *int `json:"timeoutMs,omitempty"`
+}
+
+type TuneRequest struct {
+	ScanID string `json:"scanId"`
+	ScanTuning
+}
+
+type ScanAdjustment struct {
+	At string `json:"at"`
+	ScanTuning
 }
 
 type HostMeta struct {
@@ -50,6 +182,15 @@ type HostMeta struct {
 	Errors     int64      `json:"errors"`
 	StartedAt  string     `json:"startedAt,omitempty"`
 	FinishedAt string     `json:"finishedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Harvested  int        `json:"harvested,omitempty"` // spider paths added on top of the wordlist
+	Techs      []string   `json:"techs,omitempty"`     // technologies fingerprinted on its findings
+	Cert       *CertInfo  `json:"cert,omitempty"`      // TLS certificate from the first handshake
+
+	// Landing page and favicon, fetched while baselining.
+	LandingStatus int    `json:"landingStatus,omitempty"`
+	Title         string `json:"title,omitempty"`
+	FaviconHash   *int32 `json:"faviconHash,omitempty"` // Shodan-style mmh3 of the base64 favicon
 }
 
 type ScanStartedMsg struct {
@@ -63,6 +204,13 @@ type ScanStartedMsg struct {
 	Tags       []string `json:"tags,omitempty"`
 }
 
+type HostHarvestedMsg struct {
+	ScanID    string `json:"scanId"`
+	Target    string `json:"target"`
+	Harvested int    `json:"harvested"`
+	Total     int64  `json:"total"`
+}
+
 type HostStartedMsg struct {
 	ScanID string `json:"scanId"`
 	Target string `json:"target"`
@@ -83,12 +231,20 @@ type Probe struct {
 	ScanID      string `json:"scanId"`
 	Target      string `json:"target"`
 	Path        string `json:"path"`
+	Index       int64  `json:"index"`               // position of Path in the original wordlist; -1 if harvested
+	Source      string `json:"source,omitempty"`    // spider source (robots, sitemap, html, js, words) or "variant" or "bypass"; "" = wordlist
+	Parent      string `json:"parent,omitempty"`    // finding path a variant or bypass was derived from
+	Method      string `json:"method,omitempty"`    // set when not GET
+	Technique   string `json:"technique,omitempty"` // bypass technique, e.g. "double-slash"
 	URL         string `json:"url"`
 	Status      int    `json:"status"`
 	Length      int64  `json:"length"`
 	DurationMs  int64  `json:"durationMs"`
 	ContentType string `json:"contentType,omitempty"`
 	Location    string `json:"location,omitempty"`
+	RemoteIP    string `json:"remoteIp,omitempty"`
+	Proto       string `json:"proto,omitempty"`     // protocol of the response, e.g. "HTTP/2"
+	Truncated   bool   `json:"truncated,omitempty"` // body read stopped at MaxBodyBytes
 	Error       string `json:"error,omitempty"`
 	At          string `json:"at"`
 }
@@ -97,8 +253,19 @@ type Finding struct {
 	ScanID        string `json:"scanId"`
 	Target        string `json:"target"`
 	Path          string `json:"path"`
+	Index         int64  `json:"index"`               // position of Path in the original wordlist; -1 if harvested
+	Source        string `json:"source,omitempty"`    // spider source or "variant" or "bypass"; "" = wordlist
+	Parent        string `json:"parent,omitempty"`    // finding path a variant or bypass was derived from
+	Method        string `json:"method,omitempty"`    // set when not GET
+	Technique     string `json:"technique,omitempty"` // bypass technique, e.g. "double-slash"
 	URL           string `json:"url"`
 	Status        int    `json:"status"`
 	Length        int64  `json:"length"`
+	Truncated     bool   `json:"truncated,omitempty"` // body read stopped at MaxBodyBytes
 	Soft404Likely bool   `json:"soft404_likely"`
+
+	Redirect *RedirectInfo `json:"redirect,omitempty"` // followed chain when FollowRedirects is on
+
+	Headers map[string]string `json:"headers,omitempty"` // captured response headers
+	Techs   []string          `json:"techs,omitempty"`   // fingerprinted technologies
 }
@@ -0,0 +1,53 @@
+package domain
+
+import "strings"
+
+// Variant pattern placeholders, filled from a file-like finding such as /app/config.php:
+//
+//	{path} /app/config.php   {dir} /app/   {file} config.php   {name} config   {ext} .php
+var DefaultVariantPatterns = []string{
+	"{path}.bak",
+	"{path}~",
+	"{path}.old",
+	"{path}.orig",
+	"{path}.save",
+	"{path}.swp",
+	"{dir}.{file}.swp",
+	"{dir}{name}.bak",
+	"{dir}{name}.old",
+	"{dir}{name}.zip",
+	"{dir}{name}{ext}.1",
+	"{dir}Copy%20of%20{file}",
+}
+
+var variantPlaceholders = []string{"{path}", "{dir}", "{file}", "{name}", "{ext}"}
+
+// normalizeVariantPatterns fills in the defaults and validates custom patterns.
+func (r *StartRequest) normalizeVariantPatterns(details map[string]string) {
+	r.VariantPatterns = trimNonEmpty(r.VariantPatterns)
+	if !r.Variants {
+		r.VariantPatterns = nil
+		return
+	}
+	if len(r.VariantPatterns) == 0 {
+		r.VariantPatterns = append([]string(nil), DefaultVariantPatterns...)
+		return
+	}
+	if len(r.VariantPatterns) > 100 {
+		details["variantPatterns"] = "at most 100 patterns"
+		return
+	}
+	for _, p := range r.VariantPatterns {
+		ok := false
+		for _, ph := range variantPlaceholders {
+			if strings.Contains(p, ph) {
+				ok = true
+				break
+			}
+		}
+		if !ok || strings.ContainsAny(p, "\r\n") {
+			details["variantPatterns"] = "each pattern needs a {path}, {dir}, {file}, {name} or {ext} placeholder: " + p
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Wordlist operations, applied in order to every line of the merged sources.
+const (
+	WordlistOpSubtract      = "subtract"  // drop lines present in WordlistIDs
+	WordlistOpFilter        = "filter"    // keep lines matching Pattern (drop them when Exclude)
+	WordlistOpExtension     = "extension" // keep lines ending in one of Extensions (drop them when Exclude)
+	WordlistOpLowercase     = "lowercase"
+	WordlistOpPrefix        = "prefix"        // prepend Value
+	WordlistOpSuffix        = "suffix"        // append Value
+	WordlistOpStripComments = "stripComments" // drop "#" lines and trailing " #" comments
+)
+
+type WordlistOp struct {
+	Op          string   `json:"op"`
+	WordlistIDs []string `json:"wordlistIds,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Extensions  []string `json:"extensions,omitempty"`
+	Value       string   `json:"value,omitempty"`
+	Exclude     bool     `json:"exclude,omitempty"`
+}
+
+// WordlistLineage is stored on a derived list: the sources merged (in order) and the ops applied.
+type WordlistLineage struct {
+	Sources   []string     `json:"sources"`
+	Ops       []WordlistOp `json:"ops,omitempty"`
+	CreatedAt string       `json:"createdAt"`
+}
+
+type DeriveWordlistRequest struct {
+	Name    string       `json:"name"`
+	Sources []string     `json:"sources"`
+	Ops     []WordlistOp `json:"ops"`
+}
+
+func (r *DeriveWordlistRequest) NormalizeAndValidate() map[string]string {
+	details := map[string]string{}
+	if r == nil {
+		details["request"] = "required"
+		return details
+	}
+
+	r.Name = strings.TrimSpace(r.Name)
+	r.Sources = trimNonEmpty(r.Sources)
+	if len(r.Sources) == 0 {
+		details["sources"] = "required"
+	}
+	for _, id := range r.Sources {
+		if !IsValidWordlistID(id) {
+			details["sources"] = "must be 64-char lowercase hex sha256 ids"
+			break
+		}
+	}
+	if len(r.Sources) < 2 && len(r.Ops) == 0 {
+		details["ops"] = "a single source needs at least one op"
+	}
+
+	for i := range r.Ops {
+		op := &r.Ops[i]
+		key := "ops[" + strconv.Itoa(i) + "]"
+		op.Op = strings.TrimSpace(op.Op)
+
+		switch op.Op {
+		case WordlistOpSubtract:
+			op.WordlistIDs = trimNonEmpty(op.WordlistIDs)
+			if len(op.WordlistIDs) == 0 {
+				details[key+".wordlistIds"] = "required"
+			}
+			for _, id := range op.WordlistIDs {
+				if !IsValidWordlistID(id) {
+					details[key+".wordlistIds"] = "must be 64-char lowercase hex sha256 ids"
+					break
+				}
+			}
+		case WordlistOpFilter:
+			if op.Pattern == "" {
+				details[key+".pattern"] = "required"
+			} else if _, err := regexp.Compile(op.Pattern); err != nil {
+				details[key+".pattern"] = "invalid regex"
+			}
+		case WordlistOpExtension:
+			exts := make([]string, 0, len(op.Extensions))
+			for _, e := range trimNonEmpty(op.Extensions) {
+				exts = append(exts, "."+strings.TrimPrefix(strings.ToLower(e), "."))
+			}
+			op.Extensions = exts
+			if len(exts) == 0 {
+				details[key+".extensions"] = "required"
+			}
+		case WordlistOpPrefix, WordlistOpSuffix:
+			if op.Value == "" {
+				details[key+".value"] = "required"
+			} else if strings.ContainsAny(op.Value, "\r\n") {
+				details[key+".value"] = "must be a single line"
+			}
+		case WordlistOpLowercase, WordlistOpStripComments:
+		default:
+			details[key+".op"] = "unknown op"
+		}
+	}
+
+	return details
+}
@@ -0,0 +1,70 @@
+package domain
+
+import "strings"
+
+// UpdateWordlistRequest changes the user-editable fields of a wordlist; nil fields are left alone.
+type UpdateWordlistRequest struct {
+	ID          string    `json:"id"`
+	DisplayName *string   `json:"displayName,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+}
+
+func (r *UpdateWordlistRequest) NormalizeAndValidate() map[string]string {
+	details := map[string]string{}
+	if r == nil {
+		details["request"] = "required"
+		return details
+	}
+
+	r.ID = strings.TrimSpace(r.ID)
+	if !IsValidWordlistID(r.ID) {
+		details["id"] = "must be a 64-char lowercase hex sha256"
+	}
+
+	if r.DisplayName == nil && r.Description == nil && r.Tags == nil {
+		details["request"] = "at least one field is required"
+	}
+
+	if r.DisplayName != nil {
+		v := strings.TrimSpace(*r.DisplayName)
+		r.DisplayName = &v
+		if len(v) > 200 || strings.ContainsAny(v, "\r\n") {
+			details["displayName"] = "must be a single line of at most 200 characters"
+		}
+	}
+	if r.Description != nil {
+		v := strings.TrimSpace(*r.Description)
+		r.Description = &v
+		if len(v) > 4000 {
+			details["description"] = "must be at most 4000 characters"
+		}
+	}
+	if r.Tags != nil {
+		tags := NormalizeWordlistTags(*r.Tags)
+		r.Tags = &tags
+		for _, t := range tags {
+			if len(t) > 64 {
+				details["tags"] = "tags must be at most 64 characters"
+				break
+			}
+		}
+	}
+
+	return details
+}
+
+// NormalizeWordlistTags lowercases, trims and dedupes tags, keeping their order.
+func NormalizeWordlistTags(in []string) []string {
+	out := make([]string, 0, len(in))
+	seen := map[string]struct{}{}
+	for _, t := range trimNonEmpty(in) {
+		t = strings.ToLower(t)
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
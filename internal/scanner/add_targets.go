@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+var ErrScanNotReady = errors.New("scan is not accepting new targets")
+
+// AddTargets appends targets to an active scan. The first return is false when the scan is not active.
+func (m *manager) AddTargets(id string, targets []string) (bool, []string, map[string]string, error) {
+	m.mu.Lock()
+	rt := m.runs[id]
+	m.mu.Unlock()
+	if rt == nil {
+		return false, nil, nil, nil
+	}
+
+	extend := rt.ctl.extender()
+	if extend == nil {
+		return true, nil, nil, ErrScanNotReady
+	}
+	added, rejected := extend(targets)
+	return true, added, rejected, nil
+}
+
+// extendScan builds hosts for targets added mid-scan. Each new host gets its own soft-404
+// baseline in the background and is only fed jobs once that baseline is known.
+func (e *Engine) extendScan(
+	ctx context.Context,
+	rt *runtime,
+	scanID string,
+	pr *prober,
+	lim limiters,
+	ctl *liveControls,
+	scope *domain.Scope,
+	src domain.WordlistSource,
+	total int64,
+	spider bool,
+	targets []string,
+) ([]string, map[string]string) {
+	added := make([]string, 0, len(targets))
+	rejected := map[string]string{}
+
+	for _, t := range targets {
+		target := strings.TrimSpace(t)
+		if target == "" {
+			continue
+		}
+		if ctl.host(target) != nil {
+			rejected[target] = "already in scan"
+			continue
+		}
+		base, err := url.Parse(target)
+		if err != nil || base.Scheme == "" || base.Host == "" {
+			rejected[target] = "invalid URL"
+			continue
+		}
+		if reason := scope.Check(target); reason != "" {
+			rejected[target] = "out of scope: " + reason
+			continue
+		}
+
+		h := &hostCfg{
+			target:     target,
+			base:       base,
+			sem:        newSemaphore(1),
+			total:      total,
+			state:      domain.HostStatusRunning,
+			src:        src,
+			baselining: true,
+		}
+		if !ctl.addHost(h) {
+			rejected[target] = "scan is finishing"
+			continue
+		}
+		added = append(added, target)
+
+		e.emit("host_started", domain.HostStartedMsg{ScanID: scanID, Target: target, Total: total})
+
+		startedAt := time.Now().UTC().Format(time.RFC3339)
+		rt.postMeta(func(meta *domain.Meta) {
+			meta.Targets = append(meta.Targets, target)
+			meta.TotalRequests += total
+			meta.Hosts[target] = domain.HostMeta{
+				Target:    target,
+				Status:    domain.HostStatusRunning,
+				Total:     total,
+				StartedAt: startedAt,
+			}
+		})
+
+		go func() {
+			// The baseline is built locally and published on the scan loop, which owns h.total
+			// and reads it when finalizing stopped hosts.
+			b := hostBaseline{soft404: e.calcSoft404Sig(ctx, rt, pr, ctl.timeout(), lim, h)}
+			var page []byte
+			b.landing, page = e.probeLanding(ctx, rt, pr, ctl.timeout(), lim, h)
+			if spider {
+				b.extra = e.spiderHost(ctx, rt, pr, ctl.timeout(), lim, h, page)
+				_ = dropWordlistDupes(src, &b.extra)
+			}
+			rt.postMeta(func(meta *domain.Meta) {
+				h.setBaseline(b)
+				e.recordHarvest(scanID, h, meta)
+				e.recordCert(scanID, h, scope, meta)
+				e.recordLanding(h, meta)
+			})
+		}()
+	}
+
+	return added, rejected
+}
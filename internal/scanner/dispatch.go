@@ -2,20 +2,42 @@ package scanner
 
 import "context"
 
-func feedJobsInterleaved(ctx context.Context, rt *runtime, hosts []*hostCfg, paths []string, jobs chan<- job) {
+// feedJobsInterleaved hands out one job per host per round so all targets advance through the
+// wordlist together. Paused hosts are passed over and picked up again once they resume.
+func feedJobsInterleaved(ctx context.Context, rt *runtime, ctl *liveControls, jobs chan<- job) {
 	defer close(jobs)
-	for _, p := range paths {
-		for _, h := range hosts {
+	for {
+		fed := false
+		for _, h := range ctl.hostsSnapshot() {
 			if rt != nil {
 				if !rt.waitIfPaused() {
 					return
 				}
 			}
+			j, ok := h.nextJob()
+			if !ok {
+				continue
+			}
 			select {
 			case <-ctx.Done():
 				return
-			case jobs <- job{host: h, path: p}:
+			case jobs <- j:
+				fed = true
 			}
 		}
+		if fed {
+			continue
+		}
+
+		if ctl.closeIfDrained() {
+			return
+		}
+
+		// Nothing feedable right now (paused hosts, or jobs still in flight); wait for a change.
+		select {
+		case <-ctx.Done():
+			return
+		case <-ctl.wake:
+		}
 	}
 }
@@ -1,27 +1,75 @@
 package scanner
 
 import (
+	"context"
+
 	"github.com/Pusher91/webtruder/internal/domain"
 )
 
 type Engine struct {
 	wordlists domain.WordlistStore
 	scans     domain.ScanRepo
+	scope     domain.ScopeSource
+	techs     domain.TechRuleSource
+	hitRates  domain.HitRateSource
 	emitter   domain.Emitter
 	mgr       *manager
 }
 
-func New(wordlists domain.WordlistStore, scans domain.ScanRepo, emitter domain.Emitter) *Engine {
-	e := &Engine{wordlists: wordlists, scans: scans, emitter: emitter}
+func New(wordlists domain.WordlistStore, scans domain.ScanRepo, scope domain.ScopeSource, techs domain.TechRuleSource, hitRates domain.HitRateSource, emitter domain.Emitter) *Engine {
+	e := &Engine{wordlists: wordlists, scans: scans, scope: scope, techs: techs, hitRates: hitRates, emitter: emitter}
 	e.mgr = newManager(e)
 	return e
 }
 
-func (e *Engine) Start(req domain.StartRequest) string { return e.mgr.Start(req) }
-func (e *Engine) Pause(id string) bool                 { return e.mgr.Pause(id) }
-func (e *Engine) Resume(id string) bool                { return e.mgr.Resume(id) }
-func (e *Engine) Stop(id string) bool                  { return e.mgr.Stop(id) }
-func (e *Engine) IsActive(id string) bool              { return e.mgr.IsActive(id) }
+func (e *Engine) Start(req domain.StartRequest) string     { return e.mgr.Start(req) }
+func (e *Engine) Pause(id string) bool                     { return e.mgr.Pause(id) }
+func (e *Engine) Resume(id string) bool                    { return e.mgr.Resume(id) }
+func (e *Engine) Stop(id string) bool                      { return e.mgr.Stop(id) }
+func (e *Engine) IsActive(id string) bool                  { return e.mgr.IsActive(id) }
+func (e *Engine) Tune(id string, t domain.ScanTuning) bool { return e.mgr.Tune(id, t) }
+
+// AddTargets appends targets to an active scan; see manager.AddTargets.
+func (e *Engine) AddTargets(id string, targets []string) (bool, []string, map[string]string, error) {
+	return e.mgr.AddTargets(id, targets)
+}
+
+func (e *Engine) PauseHost(id, target string) (bool, error) {
+	return e.mgr.ControlHost(id, target, domain.HostStatusPaused)
+}
+
+func (e *Engine) ResumeHost(id, target string) (bool, error) {
+	return e.mgr.ControlHost(id, target, domain.HostStatusRunning)
+}
+
+func (e *Engine) StopHost(id, target string) (bool, error) {
+	return e.mgr.ControlHost(id, target, domain.HostStatusStopped)
+}
+
+func (e *Engine) SkipHost(id, target string) (bool, error) {
+	return e.mgr.ControlHost(id, target, domain.HostStatusSkipped)
+}
+
+func (e *Engine) loadScope(ctx context.Context) (*domain.Scope, error) {
+	if e.scope == nil {
+		return &domain.Scope{}, nil
+	}
+	return e.scope.LoadScope(ctx)
+}
+
+// loadTechRules returns the built-in fingerprint rules plus the custom ones. Custom rules only
+// add detections, so an unreadable rule file falls back to the built-ins.
+func (e *Engine) loadTechRules(ctx context.Context) []domain.TechRule {
+	rules := append([]domain.TechRule(nil), domain.DefaultTechRules...)
+	if e.techs == nil {
+		return rules
+	}
+	custom, err := e.techs.LoadTechRules(ctx)
+	if err != nil {
+		return rules
+	}
+	return append(rules, custom...)
+}
 
 func (e *Engine) emit(event string, payload any) {
 	if e != nil && e.emitter != nil {
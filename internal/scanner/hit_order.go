@@ -0,0 +1,162 @@
+package scanner
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// indexedCursor reports the original wordlist position of the path last returned by Next.
+type indexedCursor interface {
+	domain.PathCursor
+	Index() int64
+}
+
+type rankedPath struct {
+	path  string
+	index int64
+	rank  int
+}
+
+// hitOrderedSource yields wordlist paths that produced findings in earlier scans first
+// (highest hit rate first), then the rest in file order.
+type hitOrderedSource struct {
+	src   domain.WordlistSource
+	first []rankedPath
+	skip  map[int64]struct{} // indexes already yielded from first
+}
+
+// prioritizeByHits ranks src by prior findings. n is how many entries were moved to the front;
+// src is returned unchanged when there is no history.
+func (e *Engine) prioritizeByHits(ctx context.Context, src domain.WordlistSource) (domain.WordlistSource, int, error) {
+	hits, err := e.priorHits()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(hits) == 0 {
+		return src, 0, nil
+	}
+
+	rank := make(map[string]int, len(hits))
+	for i, h := range hits {
+		rank[h.Path] = i
+	}
+
+	// One pass to find where the hit paths sit in this wordlist.
+	var first []rankedPath
+	cur := src.Cursor()
+	var idx int64
+	for {
+		if idx%4096 == 0 && ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		p, ok := cur.Next()
+		if !ok {
+			break
+		}
+		if r, ok := rank[p]; ok {
+			first = append(first, rankedPath{path: p, index: idx, rank: r})
+			delete(rank, p)
+		}
+		idx++
+	}
+	if err := cur.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(first) == 0 {
+		return src, 0, nil
+	}
+
+	sort.Slice(first, func(i, j int) bool { return first[i].rank < first[j].rank })
+	skip := make(map[int64]struct{}, len(first))
+	for _, rp := range first {
+		skip[rp.index] = struct{}{}
+	}
+
+	return &hitOrderedSource{src: src, first: first, skip: skip}, len(first), nil
+}
+
+// priorHits returns prior hits best first. Hit rates need every past wordlist read, so they are
+// counted when the learned list is rebuilt and only read back here; until one has been built,
+// hits are ranked by distinct hosts alone.
+func (e *Engine) priorHits() ([]domain.PathHit, error) {
+	if e.hitRates != nil {
+		hits, err := e.hitRates.HitRates()
+		if err != nil {
+			return nil, err
+		}
+		if hits != nil {
+			return rankByHitRate(hits), nil
+		}
+	}
+	hs, ok := e.scans.(domain.HitSource)
+	if !ok {
+		return nil, nil
+	}
+	hits, _, err := hs.PathHits(domain.HitsFilter{})
+	return hits, err
+}
+
+// rankByHitRate orders hits by PathHit.Rate, so a path found on 3 of 3 hosts beats one found on
+// 5 of 500; ties go to the path found on more hosts.
+func rankByHitRate(hits []domain.PathHit) []domain.PathHit {
+	out := append([]domain.PathHit(nil), hits...)
+	sort.SliceStable(out, func(i, j int) bool {
+		ri, rj := out[i].Rate(), out[j].Rate()
+		if ri != rj {
+			return ri > rj
+		}
+		return out[i].Hosts > out[j].Hosts
+	})
+	return out
+}
+
+func (s *hitOrderedSource) Cursor() domain.PathCursor {
+	return &hitOrderedCursor{s: s, idx: -1}
+}
+
+func (s *hitOrderedSource) Close() error { return s.src.Close() }
+
+type hitOrderedCursor struct {
+	s    *hitOrderedSource
+	i    int               // position in s.first
+	cur  domain.PathCursor // file-order pass, opened once s.first is exhausted
+	next int64             // index of the next path from cur
+	idx  int64
+}
+
+func (c *hitOrderedCursor) Next() (string, bool) {
+	if c.i < len(c.s.first) {
+		rp := c.s.first[c.i]
+		c.i++
+		c.idx = rp.index
+		return rp.path, true
+	}
+
+	if c.cur == nil {
+		c.cur = c.s.src.Cursor()
+	}
+	for {
+		p, ok := c.cur.Next()
+		if !ok {
+			return "", false
+		}
+		i := c.next
+		c.next++
+		if _, dup := c.s.skip[i]; dup {
+			continue
+		}
+		c.idx = i
+		return p, true
+	}
+}
+
+func (c *hitOrderedCursor) Err() error {
+	if c.cur == nil {
+		return nil
+	}
+	return c.cur.Err()
+}
+
+func (c *hitOrderedCursor) Index() int64 { return c.idx }
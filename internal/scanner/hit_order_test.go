@@ -0,0 +1,26 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+func TestRankByHitRate(t *testing.T) {
+	hits := []domain.PathHit{
+		{Path: "/common", Hosts: 5, Probed: 500},
+		{Path: "/steady", Hosts: 3, Probed: 3},
+		{Path: "/spidered", Hosts: 2},
+		{Path: "/lucky", Hosts: 1, Probed: 1},
+		{Path: "/rare", Hosts: 1, Probed: 40},
+	}
+	var got []string
+	for _, h := range rankByHitRate(hits) {
+		got = append(got, h.Path)
+	}
+	want := []string{"/steady", "/spidered", "/lucky", "/rare", "/common"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankByHitRate = %v, want %v", got, want)
+	}
+}
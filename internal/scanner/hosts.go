@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Pusher91/webtruder/internal/domain"
@@ -12,22 +13,182 @@ import (
 type hostCfg struct {
 	target  string
 	base    *url.URL
-	sem     chan struct{}
+	sem     *semaphore
+	rate    *rateGate
 	total   int64
 	soft404 soft404Sig
+	cert    *domain.CertInfo // leaf certificate from the baseline probes; set before jobs are fed
+	landing *landingInfo     // landing page and favicon; set with cert
+
+	// Dispatch state, shared by the feeder, workers and per-host controls.
+	mu       sync.Mutex
+	state    domain.HostStatus // running, paused, stopped or skipped
+	src      domain.WordlistSource
+	cur      domain.PathCursor // opened on first use
+	curIdx   int64             // position of the next path from cur, for cursors that do not report it
+	srcDone  bool
+	srcErr   error
+	pending  []job       // requeued jobs, fed before the next wordlist path
+	extra    []harvested // spider results, finding variants and bypass attempts, fed before the wordlist
+	extraPos int
+	extraSet map[string]struct{} // keys of extras queued via queueExtra
+	inflight int
+	wake     chan struct{}
+
+	// baselining holds back jobs for a host added mid-scan until its soft-404 baseline is known.
+	baselining bool
+}
+
+// nextJob returns the host's next job, or false if it is paused, halted or has nothing left to feed.
+func (h *hostCfg) nextJob() (job, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state != domain.HostStatusRunning || h.baselining {
+		return job{}, false
+	}
+
+	var j job
+	if n := len(h.pending); n > 0 {
+		j = h.pending[0]
+		h.pending = h.pending[1:]
+	} else if h.extraPos < len(h.extra) {
+		it := h.extra[h.extraPos]
+		h.extraPos++
+		j = job{
+			host: h, path: it.path, index: -1, source: it.source, parent: it.parent,
+			method: it.method, header: it.header, technique: it.technique,
+		}
+	} else if !h.srcDone && h.src != nil {
+		if h.cur == nil {
+			h.cur = h.src.Cursor()
+		}
+		p, ok := h.cur.Next()
+		if !ok {
+			h.srcErr = h.cur.Err()
+			h.srcDone = true
+			h.cur = nil
+			return job{}, false
+		}
+		idx := h.curIdx
+		h.curIdx++
+		if ic, ok := h.cur.(indexedCursor); ok {
+			idx = ic.Index()
+		}
+		j = job{host: h, path: p, index: idx}
+	} else {
+		return job{}, false
+	}
+
+	h.inflight++
+	return j, true
+}
+
+// drained reports whether the host will never produce another job.
+func (h *hostCfg) drained() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state == domain.HostStatusStopped || h.state == domain.HostStatusSkipped {
+		return true
+	}
+	return !h.baselining && (h.srcDone || h.src == nil) && len(h.pending) == 0 &&
+		h.extraPos >= len(h.extra) && h.inflight == 0
+}
+
+// hostBaseline is what a mid-scan host's baseline goroutine learned before any job is fed.
+type hostBaseline struct {
+	soft404 soft404Sig
+	extra   []harvested
+	landing *landingInfo
+}
+
+// setBaseline publishes a mid-scan host's baseline and releases its jobs. It runs on the scan
+// loop, which owns h.total, so harvested paths are counted before the first job is fed.
+func (h *hostCfg) setBaseline(b hostBaseline) {
+	h.mu.Lock()
+	h.soft404 = b.soft404
+	h.extra = b.extra
+	h.landing = b.landing
+	h.total += int64(len(b.extra))
+	h.baselining = false
+	h.mu.Unlock()
+
+	h.signal()
+}
+
+func (h *hostCfg) controlState() domain.HostStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.state
+}
+
+func (h *hostCfg) sourceErr() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.srcErr
+}
+
+func (h *hostCfg) halted() bool {
+	st := h.controlState()
+	return st == domain.HostStatusStopped || st == domain.HostStatusSkipped
+}
+
+// setState applies a per-host control; halted hosts cannot be resumed.
+func (h *hostCfg) setState(st domain.HostStatus) bool {
+	h.mu.Lock()
+	if h.state == domain.HostStatusStopped || h.state == domain.HostStatusSkipped {
+		h.mu.Unlock()
+		return false
+	}
+	h.state = st
+	h.mu.Unlock()
+
+	h.signal()
+	return true
+}
+
+// requeue hands back a job that was taken but not probed (host paused meanwhile).
+func (h *hostCfg) requeue(j job) {
+	h.mu.Lock()
+	h.pending = append(h.pending, j)
+	h.inflight--
+	h.mu.Unlock()
+
+	h.signal()
+}
+
+// jobDone marks a fed job as fully processed (or dropped).
+func (h *hostCfg) jobDone() {
+	h.mu.Lock()
+	h.inflight--
+	h.mu.Unlock()
+
+	h.signal()
+}
+
+func (h *hostCfg) signal() {
+	if h.wake == nil {
+		return
+	}
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
 }
 
 func (e *Engine) buildHosts(
 	ctx context.Context,
 	scanID string,
 	targets []string,
-	totalPaths int,
+	src domain.WordlistSource,
+	total int64,
 	perHostCap int,
+	scope *domain.Scope,
 	meta *domain.Meta,
 	markDirty func(),
 ) []*hostCfg {
 	hosts := make([]*hostCfg, 0, len(targets))
-	total := int64(totalPaths)
 
 	for _, t := range targets {
 		target := strings.TrimSpace(t)
@@ -37,8 +198,14 @@ func (e *Engine) buildHosts(
 			break
 		}
 
+		hostErr := ""
 		base, perr := url.Parse(target)
 		if perr != nil || base.Scheme == "" || base.Host == "" {
+			hostErr = "invalid URL"
+		} else if reason := scope.Check(target); reason != "" {
+			hostErr = "out of scope: " + reason
+		}
+		if hostErr != "" {
 			e.emit("host_progress", domain.HostProgressMsg{
 				ScanID:  scanID,
 				Target:  target,
@@ -60,6 +227,7 @@ func (e *Engine) buildHosts(
 					Errors:     0,
 					StartedAt:  now,
 					FinishedAt: now,
+					Error:      hostErr,
 				}
 				if markDirty != nil {
 					markDirty()
@@ -71,8 +239,10 @@ func (e *Engine) buildHosts(
 		h := &hostCfg{
 			target: target,
 			base:   base,
-			sem:    make(chan struct{}, perHostCap),
+			sem:    newSemaphore(perHostCap),
 			total:  total,
+			state:  domain.HostStatusRunning,
+			src:    src,
 		}
 		hosts = append(hosts, h)
 
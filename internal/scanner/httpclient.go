@@ -3,27 +3,55 @@ package scanner
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
 )
 
-func doReq(ctx context.Context, client *http.Client, timeout time.Duration, method, fullURL string) (*http.Response, context.CancelFunc, error) {
+// connInfo is filled in by an httptrace hook with details of the connection actually used.
+type connInfo struct {
+	remoteIP string
+}
+
+func doReq(ctx context.Context, client *http.Client, timeout time.Duration, method, fullURL string, header http.Header, ci *connInfo) (*http.Response, context.CancelFunc, error) {
 	reqCtx := ctx
 	cancel := func() {}
 	if timeout > 0 {
 		reqCtx, cancel = context.WithTimeout(ctx, timeout)
 	}
 
+	if ci != nil {
+		reqCtx = httptrace.WithClientTrace(reqCtx, &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				if info.Conn == nil {
+					return
+				}
+				if host, _, err := net.SplitHostPort(info.Conn.RemoteAddr().String()); err == nil {
+					ci.remoteIP = host
+				}
+			},
+		})
+	}
+
 	req, err := http.NewRequestWithContext(reqCtx, method, fullURL, nil)
 	if err != nil {
 		cancel()
 		return nil, func() {}, err
 	}
-	req.Header.Set("Accept-Encoding", "identity")
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "identity")
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -33,30 +61,126 @@ func doReq(ctx context.Context, client *http.Client, timeout time.Duration, meth
 	return resp, cancel, nil
 }
 
-func doGet(ctx context.Context, client *http.Client, timeout time.Duration, fullURL string) (*http.Response, context.CancelFunc, error) {
-	return doReq(ctx, client, timeout, http.MethodGet, fullURL)
+type clientOptions struct {
+	proxy     string
+	resolve   map[string]string
+	dnsServer string
+	sourceIP  net.IP
+	transport domain.TransportOptions
+	scope     *domain.Scope
+}
+
+func clientOptionsFor(req domain.StartRequest, scope *domain.Scope) clientOptions {
+	return clientOptions{
+		proxy:     req.Proxy,
+		resolve:   req.Resolve,
+		dnsServer: req.DNSServer,
+		sourceIP:  net.ParseIP(req.SourceIP),
+		transport: req.Transport,
+		scope:     scope,
+	}
+}
+
+// errExcludedIP fails a connection to an address in an excluded scope CIDR.
+var errExcludedIP = errors.New("out of scope: IP is excluded")
+
+func (o clientOptions) dialTimeout() time.Duration {
+	if o.transport.DialTimeoutMs > 0 {
+		return time.Duration(o.transport.DialTimeoutMs) * time.Millisecond
+	}
+	return 5 * time.Second
+}
+
+func (o clientOptions) tlsTimeout() time.Duration {
+	if o.transport.TLSTimeoutMs > 0 {
+		return time.Duration(o.transport.TLSTimeoutMs) * time.Millisecond
+	}
+	return 10 * time.Second
 }
 
-func newHTTPClient(perHostConcurrency int, proxyStr string) *http.Client {
+// connLimits returns the max connections and idle connections kept per host.
+func (o clientOptions) connLimits(perHostConcurrency int) (maxConns, maxIdle int) {
+	maxConns = maxInt(64, perHostConcurrency*4)
+	maxIdle = maxInt(32, perHostConcurrency*2)
+	if n := o.transport.MaxConnsPerHost; n > 0 {
+		maxConns = n
+		if maxIdle > n {
+			maxIdle = n
+		}
+	}
+	return maxConns, maxIdle
+}
+
+// dialContext applies host pins, the custom resolver and the scope's excluded CIDRs, which are
+// checked on the resolved address before connecting. When a proxy is set these only affect the
+// connection to the proxy; the proxy resolves the targets itself.
+func (o clientOptions) dialContext(d *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if o.sourceIP != nil {
+		d.LocalAddr = &net.TCPAddr{IP: o.sourceIP}
+	}
+	if o.scope != nil && len(o.scope.Exclude.CIDRs) > 0 && strings.TrimSpace(o.proxy) == "" {
+		d.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err == nil && o.scope.ExcludesIP(net.ParseIP(host)) {
+				return errExcludedIP
+			}
+			return nil
+		}
+	}
+
+	if o.dnsServer != "" {
+		d.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				dnsDialer := &net.Dialer{Timeout: d.Timeout}
+				if o.sourceIP != nil {
+					if strings.HasPrefix(network, "udp") {
+						dnsDialer.LocalAddr = &net.UDPAddr{IP: o.sourceIP}
+					} else {
+						dnsDialer.LocalAddr = &net.TCPAddr{IP: o.sourceIP}
+					}
+				}
+				return dnsDialer.DialContext(ctx, network, o.dnsServer)
+			},
+		}
+	}
+
+	if len(o.resolve) == 0 {
+		return d.DialContext
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if ip, ok := o.resolve[strings.TrimSuffix(strings.ToLower(host), ".")]; ok {
+				addr = net.JoinHostPort(ip, port)
+			}
+		}
+		return d.DialContext(ctx, network, addr)
+	}
+}
+
+func newHTTPClient(perHostConcurrency int, opts clientOptions) *http.Client {
 	if perHostConcurrency <= 0 {
 		perHostConcurrency = 1
 	}
 
 	d := &net.Dialer{
-		Timeout:   5 * time.Second,
+		Timeout:   opts.dialTimeout(),
 		KeepAlive: 30 * time.Second,
 	}
+	maxConns, maxIdle := opts.connLimits(perHostConcurrency)
 
 	tr := &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           d.DialContext,
+		DialContext:           opts.dialContext(d),
 		ForceAttemptHTTP2:     true,
 		DisableCompression:    true,
+		DisableKeepAlives:     opts.transport.DisableKeepAlive,
 		MaxIdleConns:          4096,
-		MaxIdleConnsPerHost:   maxInt(32, perHostConcurrency*2),
-		MaxConnsPerHost:       maxInt(64, perHostConcurrency*4),
+		MaxIdleConnsPerHost:   maxIdle,
+		MaxConnsPerHost:       maxConns,
 		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
+		TLSHandshakeTimeout:   opts.tlsTimeout(),
 		ExpectContinueTimeout: 1 * time.Second,
 
 		TLSClientConfig: &tls.Config{
@@ -64,8 +188,21 @@ func newHTTPClient(perHostConcurrency int, proxyStr string) *http.Client {
 		},
 	}
 
-	if strings.TrimSpace(proxyStr) != "" {
-		if u, err := url.Parse(proxyStr); err == nil && u.Scheme != "" && u.Host != "" {
+	switch opts.transport.HTTPVersion {
+	case domain.HTTPVersion11:
+		// A non-nil empty TLSNextProto turns HTTP/2 off.
+		tr.ForceAttemptHTTP2 = false
+		tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	case domain.HTTPVersion2:
+		// Offer only h2 and fail connections that do not negotiate it. Validation rejects it
+		// with a proxy, whose tunnelled requests would fall back to HTTP/1.1.
+		tr.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialH2(ctx, tr.DialContext, network, addr, opts.tlsTimeout())
+		}
+	}
+
+	if strings.TrimSpace(opts.proxy) != "" {
+		if u, err := url.Parse(opts.proxy); err == nil && u.Scheme != "" && u.Host != "" {
 			tr.Proxy = http.ProxyURL(u)
 		}
 	}
@@ -78,6 +215,31 @@ func newHTTPClient(perHostConcurrency int, proxyStr string) *http.Client {
 	}
 }
 
+// dialH2 opens a TLS connection that must negotiate HTTP/2 via ALPN.
+func dialH2(ctx context.Context, dial func(context.Context, string, string) (net.Conn, error), network, addr string, timeout time.Duration) (net.Conn, error) {
+	conn, err := dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	host, _, _ := net.SplitHostPort(addr)
+	tc := tls.Client(conn, &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         host,
+		NextProtos:         []string{"h2"},
+	})
+	hctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := tc.HandshakeContext(hctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if p := tc.ConnectionState().NegotiatedProtocol; p != "h2" {
+		_ = conn.Close()
+		return nil, errors.New("server did not negotiate HTTP/2")
+	}
+	return tc, nil
+}
+
 func protoLabel(resp *http.Response) string {
 	if resp == nil {
 		return "HTTP/1.1"
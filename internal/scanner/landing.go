@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"html"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+const (
+	maxLandingBody = spiderMaxBody // the spider parses the same body
+	maxFaviconBody = 1 << 20
+	maxTitleLen    = 200
+)
+
+var reTitle = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// landingInfo is what probeLanding learned about a host.
+type landingInfo struct {
+	status      int
+	title       string
+	faviconHash *int32
+}
+
+// probeLanding fetches the target's landing page and /favicon.ico, within the scan's limits.
+// It runs in the host's baseline goroutine, before any job is fed, and also returns the landing
+// page body when it loaded below 400 so the spider can parse it without a second fetch.
+func (e *Engine) probeLanding(ctx context.Context, rt *runtime, pr *prober, timeout time.Duration, lim limiters, h *hostCfg) (*landingInfo, []byte) {
+	if h == nil || h.base == nil {
+		return nil, nil
+	}
+	fetch := func(base *url.URL, path string, max int) (probeOutcome, []byte, bool) {
+		if rt != nil && !rt.waitIfPaused() {
+			return probeOutcome{}, nil, false
+		}
+		if h.halted() || !lim.rate.Wait(ctx) || !h.rate.Wait(ctx) || !h.sem.Acquire(ctx) {
+			return probeOutcome{}, nil, false
+		}
+		out, body := pr.fetch(ctx, timeout, base, path, max)
+		h.sem.Release()
+		return out, body, !out.wasCanceled
+	}
+
+	info := &landingInfo{}
+	out, page, ok := fetch(h.base, "", maxLandingBody)
+	if !ok {
+		return nil, nil
+	}
+	if out.errStr == "" {
+		info.status = out.status
+		info.title = pageTitle(page)
+	}
+	if out.errStr != "" || out.status >= 400 {
+		page = nil
+	}
+
+	out, body, ok := fetch(&url.URL{Scheme: h.base.Scheme, Host: h.base.Host}, "/favicon.ico", maxFaviconBody)
+	if !ok {
+		return info, page
+	}
+	if out.errStr == "" && out.status == http.StatusOK && !out.truncated && len(body) > 0 &&
+		!strings.HasPrefix(strings.ToLower(out.ct), "text/html") {
+		hash := faviconHash(body)
+		info.faviconHash = &hash
+	}
+	return info, page
+}
+
+// recordLanding stores what probeLanding found in meta, from the goroutine that owns meta.
+func (e *Engine) recordLanding(h *hostCfg, meta *domain.Meta) {
+	if h.landing == nil {
+		return
+	}
+	hm, ok := meta.Hosts[h.target]
+	if !ok {
+		return
+	}
+	hm.LandingStatus = h.landing.status
+	hm.Title = h.landing.title
+	hm.FaviconHash = h.landing.faviconHash
+	meta.Hosts[h.target] = hm
+}
+
+// pageTitle returns the first <title> of an HTML page, unescaped and with whitespace collapsed.
+func pageTitle(body []byte) string {
+	m := reTitle.FindSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	t := strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+	if len(t) > maxTitleLen {
+		// Cut on a rune boundary so the title stays valid UTF-8.
+		n := maxTitleLen
+		for n > 0 && !utf8.RuneStart(t[n]) {
+			n--
+		}
+		t = t[:n]
+	}
+	return t
+}
+
+// faviconHash is the favicon hash Shodan indexes (http.favicon.hash): MurmurHash3 x86 32-bit,
+// seed 0, over the favicon's base64 encoding with a newline after every 76 characters and at the end.
+func faviconHash(b []byte) int32 {
+	enc := base64.StdEncoding.EncodeToString(b)
+	var sb strings.Builder
+	sb.Grow(len(enc) + len(enc)/76 + 1)
+	for len(enc) > 76 {
+		sb.WriteString(enc[:76])
+		sb.WriteByte('\n')
+		enc = enc[76:]
+	}
+	sb.WriteString(enc)
+	sb.WriteByte('\n')
+	return int32(murmur3(0, []byte(sb.String())))
+}
+
+// murmur3 is MurmurHash3 x86 32-bit.
+func murmur3(seed uint32, data []byte) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+	h := seed
+	n := len(data)
+	for len(data) >= 4 {
+		k := binary.LittleEndian.Uint32(data)
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+		data = data[4:]
+	}
+
+	var k uint32
+	switch len(data) {
+	case 3:
+		k ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(data[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(n)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
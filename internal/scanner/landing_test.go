@@ -0,0 +1,74 @@
+package scanner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMurmur3(t *testing.T) {
+	tests := []struct {
+		seed uint32
+		in   string
+		want uint32
+	}{
+		{0, "", 0},
+		{1, "", 0x514e28b7},
+		{0xffffffff, "", 0x81f16f39},
+		{0, "\x00\x00\x00\x00", 0x2362f9de},
+		{0x9747b28c, "a", 0x7fa09ea6},
+		{0x9747b28c, "aa", 0x5d211726},
+		{0x9747b28c, "aaa", 0x283e0130},
+		{0x9747b28c, "aaaa", 0x5a97808a},
+		{0x9747b28c, "abcd", 0xf0478627},
+		{0x9747b28c, "Hello, world!", 0x24884cba},
+		{0, "hello", 613153351},
+		{0, "The quick brown fox jumps over the lazy dog", 776992547},
+	}
+	for _, tt := range tests {
+		if got := murmur3(tt.seed, []byte(tt.in)); got != tt.want {
+			t.Errorf("murmur3(%#x, %q) = %#x, want %#x", tt.seed, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFaviconHash(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want int32
+	}{
+		// Same as Python's mmh3.hash(codecs.encode(data, "base64")), which Shodan indexes.
+		{"short", []byte("hello"), 1155597304},
+		{"multi-line base64", bytes.Repeat([]byte{0, 1, 2, 3, 250}, 100), 1126272368},
+	}
+	for _, tt := range tests {
+		if got := faviconHash(tt.in); got != tt.want {
+			t.Errorf("%s: faviconHash = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPageTitle(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"<html><head><title> Acme\n Portal &amp; Co </title></head>", "Acme Portal & Co"},
+		{"<TITLE lang=en>Upper</TITLE><title>second</title>", "Upper"},
+		{"<html>no title</html>", ""},
+		{"<title>" + string(bytes.Repeat([]byte("x"), 300)) + "</title>", string(bytes.Repeat([]byte("x"), maxTitleLen))},
+		{"<title>x" + strings.Repeat("é", 200) + "</title>", "x" + strings.Repeat("é", (maxTitleLen-1)/2)},
+		{"<title>" + strings.Repeat("€", 100) + "</title>", strings.Repeat("€", maxTitleLen/3)},
+	}
+	for _, tt := range tests {
+		got := pageTitle([]byte(tt.in))
+		if got != tt.want {
+			t.Errorf("pageTitle(%.40q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("pageTitle(%.40q) is not valid UTF-8", tt.in)
+		}
+	}
+}
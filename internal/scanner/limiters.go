@@ -1,6 +1,8 @@
 package scanner
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/Pusher91/webtruder/internal/domain"
@@ -47,9 +49,89 @@ func tokenBucket(interval time.Duration, burst int, stop <-chan struct{}) <-chan
 	return ch
 }
 
+// rateGate wraps a tokenBucket that can be replaced while waiters are blocked on it.
+// A nil gate or a rate of 0 means unlimited.
+type rateGate struct {
+	mu      sync.Mutex
+	tok     <-chan struct{}
+	stop    chan struct{}
+	changed chan struct{}
+}
+
+func newRateGate(rps int) *rateGate {
+	g := &rateGate{changed: make(chan struct{})}
+	g.Set(rps)
+	return g
+}
+
+func (g *rateGate) Set(rps int) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stop != nil {
+		close(g.stop)
+		g.stop = nil
+	}
+	g.tok = nil
+
+	if rps > 0 {
+		interval := time.Second / time.Duration(rps)
+
+		// strict: 1 (no burst); or small burst to smooth scheduling
+		burst := minInt(rps, 10)
+		if burst < 1 {
+			burst = 1
+		}
+
+		g.stop = make(chan struct{})
+		g.tok = tokenBucket(interval, burst, g.stop)
+	}
+
+	close(g.changed)
+	g.changed = make(chan struct{})
+}
+
+// Wait blocks until a token is available; returns false if ctx is done first.
+func (g *rateGate) Wait(ctx context.Context) bool {
+	if g == nil {
+		return true
+	}
+	for {
+		g.mu.Lock()
+		tok, changed := g.tok, g.changed
+		g.mu.Unlock()
+
+		if tok == nil {
+			return true
+		}
+		select {
+		case <-tok:
+			return true
+		case <-changed:
+			// rate replaced, wait on the new bucket
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (g *rateGate) Stop() {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	if g.stop != nil {
+		close(g.stop)
+		g.stop = nil
+	}
+	g.mu.Unlock()
+}
+
 type limiters struct {
-	rateTok   <-chan struct{}
-	stopRate  chan struct{}
+	rate      *rateGate
 	probeTok  <-chan struct{}
 	stopProbe chan struct{}
 }
@@ -58,31 +140,18 @@ func (l *limiters) Stop() {
 	if l == nil {
 		return
 	}
-	if l.stopRate != nil {
-		close(l.stopRate)
-		l.stopRate = nil
-	}
+	l.rate.Stop()
 	if l.stopProbe != nil {
 		close(l.stopProbe)
 		l.stopProbe = nil
 	}
 }
 
-func (e *Engine) buildLimiters(req domain.StartRequest) limiters {
+// buildLimiters takes the global rate gate from the scan's live controls so tuning can replace it.
+func (e *Engine) buildLimiters(req domain.StartRequest, rate *rateGate) limiters {
 	var lim limiters
 
-	if req.RateLimit > 0 {
-		interval := time.Second / time.Duration(req.RateLimit)
-
-		// strict: 1 (no burst); or small burst to smooth scheduling
-		burst := minInt(req.RateLimit, 10)
-		if burst < 1 {
-			burst = 1
-		}
-
-		lim.stopRate = make(chan struct{})
-		lim.rateTok = tokenBucket(interval, burst, lim.stopRate)
-	}
+	lim.rate = rate
 
 	if req.Verbose {
 		const maxProbeSSEPerSec = 50
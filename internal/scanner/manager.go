@@ -2,7 +2,9 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/Pusher91/webtruder/internal/domain"
 )
@@ -31,6 +33,8 @@ func (m *manager) Start(req domain.StartRequest) string {
 	rt := &runtime{
 		id:       id,
 		statusCh: make(chan domain.ScanStatus, 1),
+		metaWake: make(chan struct{}, 1),
+		ctl:      newLiveControls(req),
 	}
 	rt.ctx, rt.cancel = context.WithCancel(context.Background())
 
@@ -138,6 +142,49 @@ func (m *manager) Stop(id string) bool {
 	return true
 }
 
+// Tune changes concurrency, rate limits and timeout of an active scan; the change is logged to its meta.
+func (m *manager) Tune(id string, t domain.ScanTuning) bool {
+	m.mu.Lock()
+	rt := m.runs[id]
+	m.mu.Unlock()
+	if rt == nil {
+		return false
+	}
+
+	rt.ctl.apply(t)
+
+	adj := domain.ScanAdjustment{At: time.Now().UTC().Format(time.RFC3339), ScanTuning: t}
+	rt.postMeta(func(meta *domain.Meta) { applyTuning(meta, adj) })
+	return true
+}
+
+var (
+	ErrHostNotFound = errors.New("host not found in scan")
+	ErrHostHalted   = errors.New("host is already stopped or skipped")
+)
+
+// ControlHost pauses, resumes, stops or skips one target of an active scan.
+// The first return is false when the scan is not active.
+func (m *manager) ControlHost(id, target string, status domain.HostStatus) (bool, error) {
+	m.mu.Lock()
+	rt := m.runs[id]
+	m.mu.Unlock()
+	if rt == nil {
+		return false, nil
+	}
+
+	h := rt.ctl.host(target)
+	if h == nil {
+		return true, ErrHostNotFound
+	}
+	if !h.setState(status) {
+		return true, ErrHostHalted
+	}
+
+	rt.postMeta(func(meta *domain.Meta) { applyHostStatus(meta, target, status) })
+	return true, nil
+}
+
 type runtime struct {
 	id     string
 	ctx    context.Context
@@ -149,6 +196,37 @@ type runtime struct {
 
 	desiredStatus domain.ScanStatus
 	statusCh      chan domain.ScanStatus
+
+	ctl *liveControls
+
+	// metaOps are applied to the scan meta by runScan's loop, which owns it.
+	metaOps  []func(*domain.Meta)
+	metaWake chan struct{}
+}
+
+func (rt *runtime) postMeta(fn func(*domain.Meta)) {
+	if rt == nil || fn == nil {
+		return
+	}
+	rt.mu.Lock()
+	rt.metaOps = append(rt.metaOps, fn)
+	rt.mu.Unlock()
+
+	select {
+	case rt.metaWake <- struct{}{}:
+	default:
+	}
+}
+
+func (rt *runtime) takeMetaOps() []func(*domain.Meta) {
+	if rt == nil {
+		return nil
+	}
+	rt.mu.Lock()
+	ops := rt.metaOps
+	rt.metaOps = nil
+	rt.mu.Unlock()
+	return ops
 }
 
 func (rt *runtime) signalStatus(status domain.ScanStatus) {
@@ -2,23 +2,45 @@ package scanner
 
 import "github.com/Pusher91/webtruder/internal/domain"
 
-func (e *Engine) initMeta(scanID, startedAt string, req domain.StartRequest, paths []string, wlNames []string, logPath string) domain.Meta {
+func (e *Engine) initMeta(scanID, startedAt string, req domain.StartRequest, totalPaths int64, wlNames []string, logPath string) domain.Meta {
+	var transport *domain.TransportOptions
+	if !req.Transport.IsZero() {
+		t := req.Transport
+		transport = &t
+	}
 	return domain.Meta{
-		ID:            scanID,
-		StartedAt:     startedAt,
-		Targets:       req.Targets,
-		WordlistID:    req.WordlistID,
-		WordlistNames: wlNames,
-		TotalPaths:    len(paths),
-		Concurrency:   req.Concurrency,
-		TimeoutMs:     req.TimeoutMs,
-		RateLimit:     req.RateLimit,
-		Tags:          req.Tags,
-		Verbose:       req.Verbose,
-		LogFile:       logPath,
-		TotalRequests: int64(len(paths)) * int64(len(req.Targets)),
-		Hosts:         map[string]domain.HostMeta{},
-		Status:        domain.ScanStatusRunning,
-		Proxy:         req.Proxy,
+		ID:                scanID,
+		StartedAt:         startedAt,
+		Targets:           req.Targets,
+		WordlistID:        req.WordlistID,
+		WordlistNames:     wlNames,
+		TotalPaths:        int(totalPaths),
+		Concurrency:       req.Concurrency,
+		TimeoutMs:         req.TimeoutMs,
+		RateLimit:         req.RateLimit,
+		HostRateLimit:     req.HostRateLimit,
+		Tags:              req.Tags,
+		Verbose:           req.Verbose,
+		PrioritizeHits:    req.PrioritizeHits,
+		Spider:            req.Spider,
+		VariantPatterns:   req.VariantPatterns,
+		Bypass:            req.Bypass,
+		RawHTTP:           req.RawHTTP,
+		Transport:         transport,
+		LengthMode:        req.LengthMode,
+		Compression:       req.Compression,
+		MaxBodyBytes:      req.MaxBodyBytes,
+		HeadFirst:         req.HeadFirst,
+		FollowRedirects:   req.FollowRedirects,
+		RedirectCrossHost: req.RedirectCrossHost,
+		CaptureHeaders:    req.CaptureHeaders,
+		LogFile:           logPath,
+		TotalRequests:     totalPaths * int64(len(req.Targets)),
+		Hosts:             map[string]domain.HostMeta{},
+		Status:            domain.ScanStatusRunning,
+		Proxy:             req.Proxy,
+		Resolve:           req.Resolve,
+		DNSServer:         req.DNSServer,
+		SourceIP:          req.SourceIP,
 	}
 }
@@ -1,6 +1,10 @@
 package scanner
 
-import "github.com/Pusher91/webtruder/internal/domain"
+import (
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
 
 func applyScanStatus(meta *domain.Meta, status domain.ScanStatus) {
 	if meta == nil {
@@ -12,7 +16,7 @@ func applyScanStatus(meta *domain.Meta, status domain.ScanStatus) {
 		return
 	}
 	for k, h := range meta.Hosts {
-		if h.Status == domain.HostStatusCompleted || h.Status == domain.HostStatusError {
+		if h.Status == domain.HostStatusCompleted || h.Status == domain.HostStatusError || h.Status == domain.HostStatusSkipped {
 			continue
 		}
 		switch status {
@@ -30,3 +34,41 @@ func applyScanStatus(meta *domain.Meta, status domain.ScanStatus) {
 		meta.Hosts[k] = h
 	}
 }
+
+// applyHostStatus records a per-host control action in the meta.
+func applyHostStatus(meta *domain.Meta, target string, status domain.HostStatus) {
+	if meta == nil || meta.Hosts == nil {
+		return
+	}
+	h, ok := meta.Hosts[target]
+	if !ok || h.Status == domain.HostStatusCompleted || h.Status == domain.HostStatusError {
+		return
+	}
+	switch status {
+	case domain.HostStatusRunning:
+		if meta.Status == domain.ScanStatusPaused {
+			status = domain.HostStatusPaused
+		}
+	case domain.HostStatusStopped, domain.HostStatusSkipped:
+		h.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	h.Status = status
+	meta.Hosts[target] = h
+}
+
+// applyHostControls re-asserts per-host pauses after a scan-wide resume.
+func applyHostControls(meta *domain.Meta, hosts []*hostCfg) {
+	if meta == nil || meta.Hosts == nil || meta.Status != domain.ScanStatusRunning {
+		return
+	}
+	for _, h := range hosts {
+		if h.controlState() != domain.HostStatusPaused {
+			continue
+		}
+		hm, ok := meta.Hosts[h.target]
+		if ok && hm.Status == domain.HostStatusRunning {
+			hm.Status = domain.HostStatusPaused
+			meta.Hosts[h.target] = hm
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+)
+
+// workerPool runs a resizable set of workers. Each worker gets its own quit channel so
+// the pool can shrink between jobs; done closes once the last worker has exited.
+type workerPool struct {
+	mu       sync.Mutex
+	quits    []chan struct{}
+	live     int
+	finished bool
+	done     chan struct{}
+	run      func(quit <-chan struct{})
+}
+
+func newWorkerPool(n int, run func(quit <-chan struct{})) *workerPool {
+	p := &workerPool{done: make(chan struct{}), run: run}
+	p.Resize(n)
+	return p
+}
+
+// Resize grows or shrinks the pool to n (min 1) workers. It is a no-op once the pool has drained.
+func (p *workerPool) Resize(n int) {
+	if n < 1 {
+		n = 1
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return
+	}
+
+	for len(p.quits) < n {
+		q := make(chan struct{})
+		p.quits = append(p.quits, q)
+		p.live++
+		go func() {
+			defer p.exit(q)
+			p.run(q)
+		}()
+	}
+
+	for len(p.quits) > n {
+		last := len(p.quits) - 1
+		close(p.quits[last])
+		p.quits = p.quits[:last]
+	}
+}
+
+func (p *workerPool) exit(q chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, c := range p.quits {
+		if c == q {
+			p.quits = append(p.quits[:i], p.quits[i+1:]...)
+			break
+		}
+	}
+
+	p.live--
+	if p.live == 0 && !p.finished {
+		p.finished = true
+		close(p.done)
+	}
+}
+
+func (p *workerPool) Done() <-chan struct{} { return p.done }
+
+// semaphore is a counting semaphore whose limit can change while held.
+type semaphore struct {
+	mu    sync.Mutex
+	limit int
+	held  int
+	wake  chan struct{}
+}
+
+func newSemaphore(limit int) *semaphore {
+	if limit < 1 {
+		limit = 1
+	}
+	return &semaphore{limit: limit, wake: make(chan struct{})}
+}
+
+func (s *semaphore) Acquire(ctx context.Context) bool {
+	for {
+		s.mu.Lock()
+		if s.held < s.limit {
+			s.held++
+			s.mu.Unlock()
+			return true
+		}
+		w := s.wake
+		s.mu.Unlock()
+
+		select {
+		case <-w:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (s *semaphore) Release() {
+	s.mu.Lock()
+	if s.held > 0 {
+		s.held--
+	}
+	s.broadcastLocked()
+	s.mu.Unlock()
+}
+
+func (s *semaphore) SetLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+	s.mu.Lock()
+	s.limit = limit
+	s.broadcastLocked()
+	s.mu.Unlock()
+}
+
+func (s *semaphore) broadcastLocked() {
+	close(s.wake)
+	s.wake = make(chan struct{})
+}
@@ -0,0 +1,276 @@
+package scanner
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// probeOptions are the per-scan settings that shape how a probe's response is measured.
+type probeOptions struct {
+	lengthMode  string
+	compression bool
+	maxBody     int64 // 0 = read whole bodies
+	headFirst   bool
+
+	maxRedirects      int // 0 = report 3xx as is
+	redirectCrossHost bool
+
+	fp       *fingerprinter // nil skips header capture and fingerprinting
+	keepBody int            // body bytes kept on the outcome; see prober.fetch
+}
+
+func probeOptionsFor(req domain.StartRequest, fp *fingerprinter) probeOptions {
+	return probeOptions{
+		lengthMode:  req.LengthMode,
+		compression: req.Compression,
+		maxBody:     req.MaxBodyBytes,
+		headFirst:   req.HeadFirst,
+
+		maxRedirects:      req.FollowRedirects,
+		redirectCrossHost: req.RedirectCrossHost,
+
+		fp: fp,
+	}
+}
+
+func (po probeOptions) acceptEncoding() string {
+	if po.compression {
+		return "gzip, deflate"
+	}
+	return "identity"
+}
+
+// prober sends a scan's probes, through net/http or the raw client, and measures them the same
+// way for baselines and wordlist paths alike.
+type prober struct {
+	raw   *rawClient // nil unless the scan sends raw HTTP/1.1 requests
+	opts  probeOptions
+	scope *domain.Scope // redirect hops must stay inside it
+
+	copts    clientOptions
+	mu       sync.Mutex
+	client   *http.Client // replaced by grow; read it through httpClient
+	maxConns int
+}
+
+func newProber(perHostConcurrency int, req domain.StartRequest, scope *domain.Scope, fp *fingerprinter) *prober {
+	copts := clientOptionsFor(req, scope)
+	p := &prober{
+		opts:   probeOptionsFor(req, fp),
+		scope:  scope,
+		copts:  copts,
+		client: newHTTPClient(perHostConcurrency, copts),
+	}
+	p.maxConns, _ = copts.connLimits(perHostConcurrency)
+	if req.RawHTTP {
+		p.raw = newRawClient(perHostConcurrency, copts)
+	}
+	return p
+}
+
+func (p *prober) httpClient() *http.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.client
+}
+
+// grow raises the per-host connection limits when live tuning raises per-host concurrency past
+// what they were sized for. net/http cannot change a transport's limits in place, so the client
+// is replaced; requests in flight finish on the old one. Lower concurrency is left to the host
+// semaphores.
+func (p *prober) grow(perHostConcurrency int) {
+	maxConns, maxIdle := p.copts.connLimits(perHostConcurrency)
+	p.mu.Lock()
+	if maxConns <= p.maxConns {
+		p.mu.Unlock()
+		return
+	}
+	old := p.client
+	p.client = newHTTPClient(perHostConcurrency, p.copts)
+	p.maxConns = maxConns
+	p.mu.Unlock()
+
+	old.CloseIdleConnections()
+	if p.raw != nil {
+		p.raw.setLimits(maxConns, maxIdle)
+	}
+}
+
+// probe requests path on base; method "" means GET.
+func (p *prober) probe(ctx context.Context, timeout time.Duration, method string, base *url.URL, path string, header http.Header) probeOutcome {
+	if method == "" {
+		method = http.MethodGet
+	}
+	if p.raw != nil {
+		return p.raw.probe(ctx, timeout, method, base, path, header, p.opts)
+	}
+	return performRequest(ctx, p.httpClient(), timeout, method, buildRawURL(base, path), header, p.opts)
+}
+
+// probeJob probes a worker job. With HEAD-first on, a plain GET job is sent as HEAD and only
+// repeated as GET when the HEAD answer is not a 404 or soft-404 match; it returns the method
+// whose outcome is reported ("" for GET). wait gates the follow-up GET on the scan's rate limits,
+// as the worker's own wait only paid for the HEAD, and returns false once the scan is done.
+func (p *prober) probeJob(ctx context.Context, timeout time.Duration, j job, wait func() bool) (probeOutcome, string) {
+	if !p.opts.headFirst || j.method != "" {
+		return p.probe(ctx, timeout, j.method, j.host.base, j.path, j.header), j.method
+	}
+
+	head := p.probe(ctx, timeout, http.MethodHead, j.host.base, j.path, j.header)
+	if head.wasCanceled {
+		return head, http.MethodHead
+	}
+	if !p.headSettles(j.host, head) {
+		if !wait() {
+			head.wasCanceled = true
+			return head, http.MethodHead
+		}
+		return p.probe(ctx, timeout, "", j.host.base, j.path, j.header), ""
+	}
+	// A HEAD body is empty; report the advertised size so soft-404 matching still applies.
+	if head.contentLength >= 0 && p.opts.lengthMode != domain.LengthModeFull {
+		head.length = head.contentLength
+	}
+	return head, http.MethodHead
+}
+
+// headSettles reports whether a HEAD answer is boring enough to skip the GET. Soft-404
+// signatures are body lengths, so they are only compared in body length mode.
+func (p *prober) headSettles(h *hostCfg, head probeOutcome) bool {
+	if head.errStr != "" {
+		return false
+	}
+	if head.status == http.StatusNotFound {
+		return true
+	}
+	bodyMode := p.opts.lengthMode == "" || p.opts.lengthMode == domain.LengthModeBody
+	return bodyMode && head.contentLength >= 0 && h.soft404.Match(head.status, head.contentLength)
+}
+
+// fetch is probe for a page whose content is needed: it keeps up to max bytes of the decoded
+// body, reading at most max+1 bytes off the wire so oversized bodies show as truncated.
+func (p *prober) fetch(ctx context.Context, timeout time.Duration, base *url.URL, path string, max int) (probeOutcome, []byte) {
+	po := p.opts
+	po.keepBody = max
+	po.maxBody = int64(max) + 1
+	var out probeOutcome
+	if p.raw != nil {
+		out = p.raw.probe(ctx, timeout, http.MethodGet, base, path, nil, po)
+	} else {
+		out = performRequest(ctx, p.httpClient(), timeout, http.MethodGet, buildRawURL(base, path), nil, po)
+	}
+	return out, out.body
+}
+
+func (p *prober) closeIdle() {
+	p.httpClient().CloseIdleConnections()
+	if p.raw != nil {
+		p.raw.closeIdle()
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// bodyStats is what measureBody learned about a response body.
+type bodyStats struct {
+	wire      int64 // bytes received
+	decoded   int64 // bytes after undoing Content-Encoding in decoded mode, else -1
+	truncated bool  // the body cap stopped the read with more on the way
+	prefix    []byte
+}
+
+// measureBody drains body and returns the bytes received and, in decoded mode, the bytes after
+// undoing Content-Encoding (-1 otherwise or when decoding fails). With a body cap it stops after
+// maxBody bytes and reports truncated if more were on the way. With keep > 0 it also returns up
+// to keep bytes from the start of the decoded body.
+func measureBody(body io.Reader, encoding string, po probeOptions, keep int) (bodyStats, error) {
+	src := body
+	if po.maxBody > 0 {
+		src = io.LimitReader(body, po.maxBody)
+	}
+	var pb *prefixBuffer
+	if keep > 0 {
+		pb = &prefixBuffer{n: keep}
+		src = io.TeeReader(src, pb)
+	}
+	cr := &countingReader{r: src}
+	st := bodyStats{decoded: -1}
+
+	if po.lengthMode == domain.LengthModeDecoded {
+		if dec, derr := decoder(cr, encoding); derr == nil {
+			st.decoded, derr = drainAndCount(dec)
+			if derr != nil {
+				st.decoded = -1
+			}
+		}
+	}
+
+	_, err := drainAndCount(cr)
+	st.wire = cr.n
+	if pb != nil {
+		st.prefix = decodedPrefix(pb.buf.Bytes(), encoding, keep)
+	}
+	if err != nil {
+		return st, err
+	}
+	if po.maxBody > 0 && cr.n >= po.maxBody {
+		var one [1]byte
+		if n, _ := io.ReadFull(body, one[:]); n > 0 {
+			st.decoded, st.truncated = -1, true
+		}
+	}
+	return st, nil
+}
+
+// decoder wraps r to undo a gzip or deflate Content-Encoding; other encodings pass through.
+func decoder(r io.Reader, encoding string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(r)
+	case "deflate":
+		// "deflate" should be zlib-wrapped, but some servers send a bare deflate stream.
+		br := bufio.NewReader(r)
+		if h, err := br.Peek(2); err == nil && h[0]&0x0f == 8 && (uint16(h[0])<<8|uint16(h[1]))%31 == 0 {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	default:
+		return r, nil
+	}
+}
+
+// lengthFor picks the probe length for the scan's length mode.
+func lengthFor(resp *http.Response, wire, decoded int64, po probeOptions) int64 {
+	switch po.lengthMode {
+	case domain.LengthModeDecoded:
+		if decoded >= 0 {
+			return decoded
+		}
+		return wire
+	case domain.LengthModeFull:
+		return responseBytes(resp, wire)
+	default:
+		return wire
+	}
+}
@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+func TestProbeJobHeadFirst(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("found"))
+	}))
+	defer srv.Close()
+	base, _ := url.Parse(srv.URL)
+
+	var scope domain.Scope
+	pr := newProber(1, domain.StartRequest{HeadFirst: true}, &scope, nil)
+	defer pr.closeIdle()
+	h := &hostCfg{target: srv.URL, base: base}
+
+	tests := []struct {
+		path       string
+		wantMethod string
+		wantStatus int
+		wantWaits  int
+	}{
+		{"/missing", http.MethodHead, http.StatusNotFound, 0},
+		{"/found", "", http.StatusOK, 1},
+	}
+	for _, tt := range tests {
+		waits := 0
+		out, method := pr.probeJob(context.Background(), 2*time.Second, job{host: h, path: tt.path}, func() bool {
+			waits++
+			return true
+		})
+		if method != tt.wantMethod || out.status != tt.wantStatus || waits != tt.wantWaits {
+			t.Errorf("%s: method=%q status=%d waits=%d, want %q %d %d",
+				tt.path, method, out.status, waits, tt.wantMethod, tt.wantStatus, tt.wantWaits)
+		}
+	}
+
+	// A scan that ends while the GET waits for a token reports the HEAD as canceled.
+	out, _ := pr.probeJob(context.Background(), 2*time.Second, job{host: h, path: "/found"}, func() bool { return false })
+	if !out.wasCanceled {
+		t.Errorf("refused wait: outcome not canceled: %+v", out)
+	}
+}
+
+func TestProbeExcludedIP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	port := srv.Listener.Addr().(*net.TCPAddr).Port
+	// A hostname target: only the address it resolves to falls in the excluded range.
+	base, _ := url.Parse("http://localhost:" + strconv.Itoa(port))
+
+	tests := []struct {
+		name    string
+		exclude []string
+		raw     bool
+		wantErr bool
+	}{
+		{"not excluded", []string{"10.0.0.0/8"}, false, false},
+		{"excluded", []string{"127.0.0.0/8", "::1/128"}, false, true},
+		{"excluded raw", []string{"127.0.0.0/8", "::1/128"}, true, true},
+	}
+	for _, tt := range tests {
+		scope := domain.Scope{Exclude: domain.ScopeRules{CIDRs: tt.exclude}}
+		pr := newProber(1, domain.StartRequest{RawHTTP: tt.raw}, &scope, nil)
+		out := pr.probe(context.Background(), 2*time.Second, "", base, "/", nil)
+		pr.closeIdle()
+
+		if gotErr := out.errStr != ""; gotErr != tt.wantErr {
+			t.Errorf("%s: status=%d err=%q, want error %v", tt.name, out.status, out.errStr, tt.wantErr)
+		}
+		if tt.wantErr && !strings.Contains(out.errStr, errExcludedIP.Error()) {
+			t.Errorf("%s: err = %q, want out of scope", tt.name, out.errStr)
+		}
+	}
+}
@@ -8,7 +8,8 @@ import (
 
 type hostAgg struct {
 	done     int64
-	lastDone int64
+	sent     int64 // requests, which HEAD-first and redirect following push past done
+	lastSent int64
 	lastT    time.Time
 	findings int64
 	errs     int64
@@ -24,14 +25,14 @@ func (e *Engine) maybeEmitProgress(scanID string, h *hostCfg, a *hostAgg, now ti
 
 	if a.lastT.IsZero() {
 		a.lastT = now
-		a.lastDone = d
+		a.lastSent = a.sent
 	}
 
 	if now.Sub(a.lastT) < 500*time.Millisecond && d != total {
 		return false, domain.HostProgressMsg{}
 	}
 
-	delta := d - a.lastDone
+	delta := a.sent - a.lastSent
 	secs := now.Sub(a.lastT).Seconds()
 	rps := 0
 	if secs > 0 {
@@ -52,7 +53,7 @@ func (e *Engine) maybeEmitProgress(scanID string, h *hostCfg, a *hostAgg, now ti
 		Errors:  a.errs,
 	}
 
-	a.lastDone = d
+	a.lastSent = a.sent
 	a.lastT = now
 	return true, msg
 }
@@ -101,13 +102,24 @@ func (e *Engine) finalizeStoppedHostsAgg(
 			Errors:  errs,
 		})
 
+		status := domain.HostStatusStopped
+		if h.controlState() == domain.HostStatusSkipped {
+			status = domain.HostStatusSkipped
+		}
+
 		hm := meta.Hosts[h.target]
-		hm.Status = domain.HostStatusStopped
+		if err := h.sourceErr(); err != nil {
+			status = domain.HostStatusError
+			hm.Error = "wordlist read: " + err.Error()
+		}
+		hm.Status = status
 		hm.Checked = checked
 		hm.Total = total
 		hm.Findings = findings
 		hm.Errors = errs
-		hm.FinishedAt = now
+		if hm.FinishedAt == "" {
+			hm.FinishedAt = now
+		}
 		meta.Hosts[h.target] = hm
 		meta.TotalFindings = totalFindings
 		meta.TotalErrors = totalErrors
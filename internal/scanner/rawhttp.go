@@ -0,0 +1,350 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// rawUserAgent matches what net/http sends, so raw probes look like the soft-404 baseline requests.
+const rawUserAgent = "Go-http-client/1.1"
+
+// rawClient writes HTTP/1.1 requests by hand so the request target reaches the server exactly as
+// it appears in the wordlist, without net/url parsing or path cleaning. Connections are pooled per
+// scheme and host; with a proxy, each connection is tunnelled through CONNECT.
+type rawClient struct {
+	dial       func(ctx context.Context, network, addr string) (net.Conn, error)
+	proxy      *url.URL
+	tlsTimeout time.Duration
+	keepAlive  bool
+
+	mu       sync.Mutex
+	maxConns int // per host; only connections in use hold a slot, so open ones never exceed it
+	maxIdle  int
+	idle     map[string][]*rawConn
+	slots    map[string]*semaphore
+}
+
+type rawConn struct {
+	net.Conn
+	br       *bufio.Reader
+	remoteIP string
+}
+
+func newRawClient(perHostConcurrency int, opts clientOptions) *rawClient {
+	d := &net.Dialer{
+		Timeout:   opts.dialTimeout(),
+		KeepAlive: 30 * time.Second,
+	}
+	maxConns, maxIdle := opts.connLimits(perHostConcurrency)
+	c := &rawClient{
+		dial:       opts.dialContext(d),
+		tlsTimeout: opts.tlsTimeout(),
+		keepAlive:  !opts.transport.DisableKeepAlive,
+		maxConns:   maxConns,
+		maxIdle:    maxIdle,
+		idle:       map[string][]*rawConn{},
+		slots:      map[string]*semaphore{},
+	}
+	if strings.TrimSpace(opts.proxy) != "" {
+		if u, err := url.Parse(opts.proxy); err == nil && u.Scheme != "" && u.Host != "" {
+			c.proxy = u
+		}
+	}
+	return c
+}
+
+// probe sends method and the raw request target (base path joined with path, unescaped) to base.
+func (c *rawClient) probe(ctx context.Context, timeout time.Duration, method string, base *url.URL, path string, header http.Header, po probeOptions) (out probeOutcome) {
+	t0 := time.Now()
+	out = probeOutcome{bodyBytes: -1, length: -1, contentLength: -1}
+	defer func() { out.durMs = time.Since(t0).Milliseconds() }()
+
+	reqCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	target := joinPath(base.EscapedPath(), path)
+	var msg strings.Builder
+	msg.WriteString(method + " " + target + " HTTP/1.1\r\n")
+	msg.WriteString("Host: " + base.Host + "\r\n")
+	msg.WriteString("User-Agent: " + rawUserAgent + "\r\n")
+	for k, vs := range header {
+		for _, v := range vs {
+			msg.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+	if !c.keepAlive {
+		msg.WriteString("Connection: close\r\n")
+	}
+	msg.WriteString("Accept-Encoding: " + po.acceptEncoding() + "\r\n\r\n")
+
+	if !c.acquire(reqCtx, base) {
+		out.fail(ctx, reqCtx.Err())
+		return out
+	}
+	defer c.release(base)
+
+	// A pooled connection the server has since closed fails before any response byte; retry once fresh.
+	for attempt := 0; ; attempt++ {
+		conn, reused, err := c.get(reqCtx, base)
+		if err != nil {
+			out.fail(ctx, err)
+			return out
+		}
+		out.remoteIP = conn.remoteIP
+
+		release := conn.watch(reqCtx)
+		resp, err := conn.roundTrip(reqCtx, msg.String(), method)
+		if err != nil {
+			release()
+			_ = conn.Close()
+			if reused && attempt == 0 && reqCtx.Err() == nil {
+				continue
+			}
+			out.fail(ctx, err)
+			return out
+		}
+
+		out.readResponse(resp, po)
+		release()
+		if out.errStr == "" && !out.truncated && !resp.Close && c.keepAlive {
+			c.put(base, conn)
+		} else {
+			_ = conn.Close()
+		}
+		return out
+	}
+}
+
+// watch applies reqCtx's deadline to the connection and makes cancelling reqCtx unblock any
+// pending read or write. The returned func stops watching; call it before pooling the connection.
+func (rc *rawConn) watch(reqCtx context.Context) func() {
+	if dl, ok := reqCtx.Deadline(); ok {
+		_ = rc.SetDeadline(dl)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-reqCtx.Done():
+			_ = rc.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// roundTrip writes msg and reads the response head; the body is read by the caller.
+func (rc *rawConn) roundTrip(reqCtx context.Context, msg, method string) (*http.Response, error) {
+	if _, err := io.WriteString(rc, msg); err != nil {
+		return nil, ctxErr(reqCtx, err)
+	}
+	resp, err := http.ReadResponse(rc.br, &http.Request{Method: method})
+	if err != nil {
+		return nil, ctxErr(reqCtx, err)
+	}
+	resp.Body = &deadlineBody{ReadCloser: resp.Body, ctx: reqCtx}
+	if tc, ok := rc.Conn.(*tls.Conn); ok {
+		st := tc.ConnectionState()
+		resp.TLS = &st
+	}
+	return resp, nil
+}
+
+// deadlineBody reports a cancelled or expired request context instead of the raw i/o error.
+type deadlineBody struct {
+	io.ReadCloser
+	ctx context.Context
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = ctxErr(b.ctx, err)
+	}
+	return n, err
+}
+
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func rawKey(base *url.URL) string {
+	return strings.ToLower(base.Scheme) + "://" + strings.ToLower(base.Host)
+}
+
+// acquire takes one of base's connection slots, waiting while all are in use.
+func (c *rawClient) acquire(ctx context.Context, base *url.URL) bool {
+	key := rawKey(base)
+	c.mu.Lock()
+	slots := c.slots[key]
+	if slots == nil {
+		slots = newSemaphore(c.maxConns)
+		c.slots[key] = slots
+	}
+	c.mu.Unlock()
+
+	return slots.Acquire(ctx)
+}
+
+func (c *rawClient) release(base *url.URL) {
+	c.mu.Lock()
+	slots := c.slots[rawKey(base)]
+	c.mu.Unlock()
+	slots.Release()
+}
+
+// setLimits changes the per-host connection and idle limits, including for hosts already in use.
+func (c *rawClient) setLimits(maxConns, maxIdle int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxConns, c.maxIdle = maxConns, maxIdle
+	for _, slots := range c.slots {
+		slots.SetLimit(maxConns)
+	}
+}
+
+func (c *rawClient) get(ctx context.Context, base *url.URL) (*rawConn, bool, error) {
+	key := rawKey(base)
+	c.mu.Lock()
+	if list := c.idle[key]; len(list) > 0 {
+		conn := list[len(list)-1]
+		c.idle[key] = list[:len(list)-1]
+		c.mu.Unlock()
+		return conn, true, nil
+	}
+	c.mu.Unlock()
+
+	conn, err := c.connect(ctx, base)
+	return conn, false, err
+}
+
+func (c *rawClient) put(base *url.URL, conn *rawConn) {
+	_ = conn.SetDeadline(time.Time{})
+	key := rawKey(base)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.idle[key]) >= c.maxIdle {
+		_ = conn.Close()
+		return
+	}
+	c.idle[key] = append(c.idle[key], conn)
+}
+
+// closeIdle closes every pooled connection.
+func (c *rawClient) closeIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, list := range c.idle {
+		for _, conn := range list {
+			_ = conn.Close()
+		}
+		delete(c.idle, key)
+	}
+}
+
+func (c *rawClient) connect(ctx context.Context, base *url.URL) (*rawConn, error) {
+	addr := net.JoinHostPort(base.Hostname(), strconv.Itoa(domain.URLPort(base)))
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if c.proxy != nil {
+		conn, err = c.tunnel(ctx, addr)
+	} else {
+		conn, err = c.dial(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	remoteIP := ""
+	if host, _, err := net.SplitHostPort(conn.RemoteAddr().String()); err == nil {
+		remoteIP = host
+	}
+
+	if strings.EqualFold(base.Scheme, "https") {
+		tc := tls.Client(conn, &tls.Config{
+			InsecureSkipVerify: true,
+			ServerName:         base.Hostname(),
+			NextProtos:         []string{"http/1.1"},
+		})
+		hctx, cancel := context.WithTimeout(ctx, c.tlsTimeout)
+		err := tc.HandshakeContext(hctx)
+		cancel()
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tc
+	}
+
+	return &rawConn{Conn: conn, br: bufio.NewReader(conn), remoteIP: remoteIP}, nil
+}
+
+// tunnel opens a CONNECT tunnel to addr through the configured proxy.
+func (c *rawClient) tunnel(ctx context.Context, addr string) (net.Conn, error) {
+	proxyAddr := net.JoinHostPort(c.proxy.Hostname(), strconv.Itoa(domain.URLPort(c.proxy)))
+	conn, err := c.dial(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(c.proxy.Scheme, "https") {
+		tc := tls.Client(conn, &tls.Config{InsecureSkipVerify: true, ServerName: c.proxy.Hostname()})
+		if err := tc.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tc
+	}
+
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+	msg := "CONNECT " + addr + " HTTP/1.1\r\nHost: " + addr + "\r\n"
+	if u := c.proxy.User; u != nil {
+		pw, _ := u.Password()
+		msg += "Proxy-Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(u.Username()+":"+pw)) + "\r\n"
+	}
+	msg += "\r\n"
+	if _, err := io.WriteString(conn, msg); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT %s: %s", addr, resp.Status)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
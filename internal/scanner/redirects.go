@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// reLoginPath matches login-like path segments: /login, /wp-login.php, /users/sign_in, /sso/...
+// The login word must be a whole part of the segment, split off by "-", "_" or ".", so
+// /blogin-stats and /catalog-index do not count.
+var reLoginPath = regexp.MustCompile(`(?i)(^|/)((\w+[-_.])*(log[-_]?in|sign[-_]?in|log[-_]?on)([-_.]\w+)*|auth|authenticate|sso|cas|oauth2?|saml2?)(/|\.|$)`)
+
+func isRedirect(status int) bool { return status >= 300 && status <= 399 && status != 304 }
+
+// followRedirects follows out's Location up to the scan's hop limit. Hops are checked against
+// scope and, unless cross-host redirects are allowed, must stay on the host's name. wait gates
+// each hop on the scan's rate limits and returns false once the scan is done.
+func (p *prober) followRedirects(ctx context.Context, timeout time.Duration, h *hostCfg, fromURL string, out probeOutcome, wait func() bool) *domain.RedirectInfo {
+	info := &domain.RedirectInfo{FinalURL: fromURL, FinalStatus: out.status, FinalLength: out.length}
+	seen := map[string]struct{}{fromURL: {}}
+	cur, loc := fromURL, out.loc
+
+	for isRedirect(info.FinalStatus) && loc != "" {
+		next, err := resolveLocation(cur, loc)
+		if err != nil {
+			info.Chain = append(info.Chain, domain.RedirectHop{URL: loc, Error: err.Error()})
+			info.Stopped = domain.RedirectStopError
+			break
+		}
+		nextURL := next.String()
+		if len(info.Chain) >= p.opts.maxRedirects {
+			info.Stopped = domain.RedirectStopMaxHops
+			break
+		}
+		hop := domain.RedirectHop{URL: nextURL}
+		if _, dup := seen[nextURL]; dup {
+			info.Chain = append(info.Chain, hop)
+			info.Stopped = domain.RedirectStopLoop
+			break
+		}
+		seen[nextURL] = struct{}{}
+		if !p.opts.redirectCrossHost && !strings.EqualFold(next.Hostname(), h.base.Hostname()) {
+			info.Chain = append(info.Chain, hop)
+			info.Stopped = domain.RedirectStopOffHost
+			break
+		}
+		if p.scope.Check(nextURL) != "" {
+			info.Chain = append(info.Chain, hop)
+			info.Stopped = domain.RedirectStopOutOfScope
+			break
+		}
+		if !wait() {
+			break
+		}
+
+		path := next.EscapedPath()
+		if next.RawQuery != "" {
+			path += "?" + next.RawQuery
+		}
+		hopOut := p.probe(ctx, timeout, "", &url.URL{Scheme: next.Scheme, Host: next.Host}, path, nil)
+		if hopOut.wasCanceled {
+			break
+		}
+		hop.Status, hop.Length, hop.Error = hopOut.status, hopOut.length, hopOut.errStr
+		info.Chain = append(info.Chain, hop)
+		if hopOut.errStr != "" {
+			info.Stopped = domain.RedirectStopError
+			break
+		}
+
+		info.FinalURL, info.FinalStatus, info.FinalLength = nextURL, hopOut.status, hopOut.length
+		cur, loc = nextURL, hopOut.loc
+	}
+
+	info.Class = classifyRedirect(fromURL, info)
+	return info
+}
+
+func resolveLocation(cur, loc string) (*url.URL, error) {
+	base, err := url.Parse(cur)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := url.Parse(strings.TrimSpace(loc))
+	if err != nil {
+		return nil, err
+	}
+	return base.ResolveReference(ref), nil
+}
+
+// classifyRedirect names what a chain most likely is, preferring login pages over the shape
+// of the first hop.
+func classifyRedirect(fromURL string, info *domain.RedirectInfo) string {
+	if len(info.Chain) == 0 {
+		return domain.RedirectOther
+	}
+	for _, hop := range info.Chain {
+		if u, err := url.Parse(hop.URL); err == nil && reLoginPath.MatchString(u.Path) {
+			return domain.RedirectLogin
+		}
+	}
+
+	from, err1 := url.Parse(fromURL)
+	first, err2 := url.Parse(info.Chain[0].URL)
+	if err1 != nil || err2 != nil {
+		return domain.RedirectOther
+	}
+	sameHost := strings.EqualFold(first.Hostname(), from.Hostname())
+	switch {
+	case sameHost && first.Scheme == from.Scheme && first.EscapedPath() == from.EscapedPath()+"/":
+		return domain.RedirectTrailingSlash
+	case sameHost && from.Scheme == "http" && first.Scheme == "https" && first.EscapedPath() == from.EscapedPath():
+		return domain.RedirectHTTPS
+	}
+
+	last, err := url.Parse(info.Chain[len(info.Chain)-1].URL)
+	if err == nil && !strings.EqualFold(last.Hostname(), from.Hostname()) {
+		return domain.RedirectOffHost
+	}
+	return domain.RedirectOther
+}
@@ -0,0 +1,122 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+func TestClassifyRedirect(t *testing.T) {
+	tests := []struct {
+		from  string
+		chain []string
+		want  string
+	}{
+		{"http://a.test/x", nil, domain.RedirectOther},
+		{"http://a.test/x", []string{"http://a.test/x/"}, domain.RedirectTrailingSlash},
+		{"http://a.test/x", []string{"http://A.TEST/x/"}, domain.RedirectTrailingSlash},
+		{"http://a.test/x", []string{"https://a.test/x/"}, domain.RedirectOther},
+		{"http://a.test/x", []string{"https://a.test/x"}, domain.RedirectHTTPS},
+		{"https://a.test/x", []string{"http://a.test/x"}, domain.RedirectOther},
+		{"http://a.test/private", []string{"http://a.test/account/login?next=/private"}, domain.RedirectLogin},
+		{"http://a.test/p", []string{"http://a.test/p/", "http://a.test/wp-login.php"}, domain.RedirectLogin},
+		{"http://a.test/p", []string{"https://sso.corp.test/saml2/idp"}, domain.RedirectLogin},
+		{"http://a.test/p", []string{"http://a.test/p/", "http://b.test/"}, domain.RedirectTrailingSlash},
+		{"http://a.test/p", []string{"http://a.test/q", "http://b.test/"}, domain.RedirectOffHost},
+		{"http://a.test/p", []string{"http://a.test/catalog"}, domain.RedirectOther},
+		{"http://a.test/p", []string{"http://a.test/blogin-stats"}, domain.RedirectOther},
+		{"http://a.test/p", []string{"http://a.test/catalog-index"}, domain.RedirectOther},
+		{"http://a.test/p", []string{"http://a.test/users/sign_in"}, domain.RedirectLogin},
+		{"http://a.test/p", []string{"http://a.test/admin_login.aspx"}, domain.RedirectLogin},
+		{"http://a.test/p", []string{"http://a.test/log-in/"}, domain.RedirectLogin},
+		{"http://a.test/p", []string{"http://a.test/auth/callback"}, domain.RedirectLogin},
+		{"http://a.test/p", []string{"http://a.test/author"}, domain.RedirectOther},
+	}
+	for _, tt := range tests {
+		info := &domain.RedirectInfo{}
+		for _, u := range tt.chain {
+			info.Chain = append(info.Chain, domain.RedirectHop{URL: u})
+		}
+		if got := classifyRedirect(tt.from, info); got != tt.want {
+			t.Errorf("classifyRedirect(%s, %v) = %q, want %q", tt.from, tt.chain, got, tt.want)
+		}
+	}
+}
+
+func TestFollowRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dir":
+			http.Redirect(w, r, "/dir/", http.StatusMovedPermanently)
+		case "/dir/":
+			w.Write([]byte("listing"))
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		case "/c":
+			http.Redirect(w, r, "/a", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	base, _ := url.Parse(srv.URL)
+	port := base.Port()
+	sameHost := srv.URL
+	otherName := "http://localhost:" + port
+
+	tests := []struct {
+		name       string
+		maxHops    int
+		crossHost  bool
+		scope      domain.Scope
+		path       string
+		loc        string
+		wantStop   string
+		wantStatus int
+		wantHops   int
+	}{
+		{name: "same host", maxHops: 3, path: "/dir", loc: sameHost + "/dir/", wantStatus: 200, wantHops: 1},
+		{name: "relative location", maxHops: 3, path: "/dir", loc: "/dir/", wantStatus: 200, wantHops: 1},
+		{name: "loop", maxHops: 5, path: "/a", loc: "/b", wantStop: domain.RedirectStopLoop, wantStatus: 302, wantHops: 3},
+		{name: "max hops", maxHops: 1, path: "/a", loc: "/b", wantStop: domain.RedirectStopMaxHops, wantStatus: 302, wantHops: 1},
+		{name: "off host", maxHops: 3, path: "/dir", loc: otherName + "/dir/", wantStop: domain.RedirectStopOffHost, wantStatus: 302, wantHops: 1},
+		{name: "cross host allowed", maxHops: 3, crossHost: true, path: "/dir", loc: otherName + "/dir/", wantStatus: 200, wantHops: 1},
+		{
+			name: "cross host out of scope", maxHops: 3, crossHost: true,
+			scope: domain.Scope{Include: domain.ScopeRules{Domains: []string{"127.0.0.1"}}},
+			path:  "/dir", loc: otherName + "/dir/", wantStop: domain.RedirectStopOutOfScope, wantStatus: 302, wantHops: 1,
+		},
+		{
+			name: "same host excluded port", maxHops: 3,
+			scope: domain.Scope{Exclude: domain.ScopeRules{Ports: []int{9}}},
+			path:  "/dir", loc: "http://" + base.Hostname() + ":9/dir/", wantStop: domain.RedirectStopOutOfScope, wantStatus: 302, wantHops: 1,
+		},
+	}
+	for _, tt := range tests {
+		req := domain.StartRequest{FollowRedirects: tt.maxHops, RedirectCrossHost: tt.crossHost}
+		scope := tt.scope
+		pr := newProber(1, req, &scope, nil)
+		h := &hostCfg{target: srv.URL, base: base}
+
+		from := srv.URL + tt.path
+		out := probeOutcome{status: http.StatusFound, loc: tt.loc}
+		info := pr.followRedirects(context.Background(), 2*time.Second, h, from, out, func() bool { return true })
+
+		if info.Stopped != tt.wantStop || info.FinalStatus != tt.wantStatus || len(info.Chain) != tt.wantHops {
+			t.Errorf("%s: stopped=%q status=%d hops=%d, want %q %d %d (chain %+v)",
+				tt.name, info.Stopped, info.FinalStatus, len(info.Chain), tt.wantStop, tt.wantStatus, tt.wantHops, info.Chain)
+		}
+		if tt.wantStop == "" && !strings.HasSuffix(info.FinalURL, "/dir/") {
+			t.Errorf("%s: FinalURL = %q", tt.name, info.FinalURL)
+		}
+		pr.closeIdle()
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"net/http"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/Pusher91/webtruder/internal/domain"
@@ -22,12 +21,36 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 		ctx = rt.ctx
 	}
 
-	paths, wlNames, err := e.loadWordlist(ctx, req.WordlistID)
-	if err != nil || len(paths) == 0 {
+	src, totalPaths, wlNames, err := e.loadWordlist(ctx, req.WordlistID)
+	if err != nil {
+		e.emit("scan_done", map[string]any{"scanId": scanID, "error": "failed to read wordlist"})
+		return
+	}
+	defer src.Close()
+	if totalPaths == 0 {
 		e.emit("scan_done", map[string]any{"scanId": scanID, "error": "failed to read wordlist"})
 		return
 	}
 
+	prioritized := 0
+	if req.PrioritizeHits {
+		if src, prioritized, err = e.prioritizeByHits(ctx, src); err != nil {
+			e.emit("scan_done", map[string]any{"scanId": scanID, "error": "failed to rank wordlist by prior hits"})
+			return
+		}
+	}
+
+	// Scope is snapshotted once per scan; fail closed if it cannot be read.
+	scope, err := e.loadScope(ctx)
+	if err != nil {
+		e.emit("scan_done", map[string]any{"scanId": scanID, "error": "failed to read scope"})
+		return
+	}
+	if msg := req.CheckScope(scope)["resolve"]; msg != "" {
+		e.emit("scan_done", map[string]any{"scanId": scanID, "error": "pinned IP " + msg})
+		return
+	}
+
 	rec, err := e.scans.OpenRecorder(ctx, scanID, req.Verbose)
 	if err != nil {
 		e.emit("scan_done", map[string]any{"scanId": scanID, "error": "failed to open scan recorder"})
@@ -41,11 +64,18 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 		logPath = ""
 	}
 
-	lim := e.buildLimiters(req)
+	ctl := newLiveControls(req)
+	if rt != nil && rt.ctl != nil {
+		ctl = rt.ctl
+	}
+	defer ctl.stop()
+
+	lim := e.buildLimiters(req, ctl.rate)
 	defer lim.Stop()
 
 	startedAt := time.Now().UTC().Format(time.RFC3339)
-	meta := e.initMeta(scanID, startedAt, req, paths, wlNames, logPath)
+	meta := e.initMeta(scanID, startedAt, req, totalPaths, wlNames, logPath)
+	meta.PrioritizedPaths = prioritized
 
 	dirty := false
 	markDirty := func() { dirty = true }
@@ -59,8 +89,10 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 	}
 
 	var statusCh <-chan domain.ScanStatus
+	var metaWake <-chan struct{}
 	if rt != nil {
 		statusCh = rt.statusCh
+		metaWake = rt.metaWake
 		if st := rt.desiredStatusSnapshot(); st != "" {
 			applyScanStatus(&meta, st)
 			markDirty()
@@ -76,42 +108,60 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 		ScanID:     scanID,
 		Targets:    req.Targets,
 		WordlistID: req.WordlistID,
-		TotalPaths: len(paths),
+		TotalPaths: int(totalPaths),
 		StartedAt:  startedAt,
 		Verbose:    req.Verbose,
 		LogFile:    logPath,
 		Tags:       req.Tags,
 	})
 
-	timeout := time.Duration(req.TimeoutMs) * time.Millisecond
-	workers := sanitizeWorkers(req.Concurrency)
+	workers := ctl.workerCount()
 	perHostCap := perHostCapFor(workers, len(req.Targets))
-	client := newHTTPClient(perHostCap, req.Proxy)
+	pr := newProber(perHostCap, req, scope, newFingerprinter(e.loadTechRules(ctx), req.CaptureHeaders))
+	ctl.attachProber(pr)
 
-	hosts := e.buildHosts(ctx, scanID, req.Targets, len(paths), perHostCap, &meta, markDirty)
+	hosts := e.buildHosts(ctx, scanID, req.Targets, src, totalPaths, perHostCap, scope, &meta, markDirty)
+	ctl.attachHosts(hosts)
 
 	// NEW: per-host soft-404 baseline probes (GUID, GUID/, GUID.html, GUID.png)
-	e.computeSoft404Baselines(ctx, rt, client, timeout, lim, hosts, workers)
+	e.computeSoft404Baselines(ctx, rt, pr, ctl.timeout(), lim, hosts, workers, req.Spider)
+	if req.Spider {
+		lists := make([]*[]harvested, 0, len(hosts))
+		for _, h := range hosts {
+			lists = append(lists, &h.extra)
+		}
+		_ = dropWordlistDupes(src, lists...)
+		for _, h := range hosts {
+			h.countHarvest()
+			e.recordHarvest(scanID, h, &meta)
+		}
+		markDirty()
+	}
+	for _, h := range hosts {
+		e.recordCert(scanID, h, scope, &meta)
+		e.recordLanding(h, &meta)
+	}
+	markDirty()
 
 	// Keep buffers low so pause takes effect quickly.
 	jobs := make(chan job, workers)
 	results := make(chan probeResult, workers)
 
-	var wg sync.WaitGroup
-	wg.Add(workers)
-	for i := 0; i < workers; i++ {
-		go func() {
-			defer wg.Done()
-			e.workerLoop(ctx, scanID, client, timeout, lim, jobs, results)
-		}()
-	}
+	pool := newWorkerPool(ctl.workerCount(), func(quit <-chan struct{}) {
+		e.workerLoop(ctx, scanID, pr, ctl, lim, quit, jobs, results)
+	})
+	ctl.attachPool(pool)
 
 	go func() {
-		wg.Wait()
+		<-pool.Done()
 		close(results)
 	}()
 
-	go feedJobsInterleaved(ctx, rt, hosts, paths, jobs)
+	ctl.setExtender(func(targets []string) ([]string, map[string]string) {
+		return e.extendScan(ctx, rt, scanID, pr, lim, ctl, scope, src, totalPaths, req.Spider, targets)
+	})
+
+	go feedJobsInterleaved(ctx, rt, ctl, jobs)
 
 	aggs := make(map[string]*hostAgg, len(hosts))
 	for _, h := range hosts {
@@ -128,6 +178,14 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 		select {
 		case st := <-statusCh:
 			applyScanStatus(&meta, st)
+			applyHostControls(&meta, ctl.hostsSnapshot())
+			markDirty()
+			flush(true)
+
+		case <-metaWake:
+			for _, op := range rt.takeMetaOps() {
+				op(&meta)
+			}
 			markDirty()
 			flush(true)
 
@@ -136,13 +194,16 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 
 		case res, ok := <-results:
 			if !ok {
-				client.CloseIdleConnections()
+				pr.closeIdle()
 
-				stopped := rt != nil && rt.ctx.Err() != nil
-				if stopped {
-					e.finalizeStoppedHostsAgg(scanID, hosts, aggs, &meta, totalFindings, totalErrors, markDirty)
+				for _, op := range rt.takeMetaOps() {
+					op(&meta)
 				}
 
+				// Finalizes hosts cut short by a scan stop or a per-host stop/skip.
+				stopped := rt != nil && rt.ctx.Err() != nil
+				e.finalizeStoppedHostsAgg(scanID, ctl.hostsSnapshot(), aggs, &meta, totalFindings, totalErrors, markDirty)
+
 				meta.FinishedAt = time.Now().UTC().Format(time.RFC3339)
 				meta.TotalFindings = totalFindings
 				meta.TotalErrors = totalErrors
@@ -172,6 +233,7 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 
 			now := time.Now()
 			a.done++
+			a.sent += int64(res.requests)
 
 			out := res.out
 
@@ -184,6 +246,9 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 			if isFinding && res.host != nil && res.host.soft404.Match(out.status, out.length) {
 				isFinding = false
 			}
+			if res.technique != "" {
+				isFinding = bypassSucceeded(res.host, out)
+			}
 
 			isErrReq := out.errStr != "" ||
 				out.status == http.StatusTooManyRequests ||
@@ -209,26 +274,83 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 					ScanID:        scanID,
 					Target:        res.host.target,
 					Path:          res.path,
+					Index:         res.index,
+					Source:        res.source,
+					Parent:        res.parent,
+					Method:        res.method,
+					Technique:     res.technique,
 					URL:           res.url,
 					Status:        out.status,
 					Length:        out.length,
+					Truncated:     out.truncated,
 					Soft404Likely: false,
+					Redirect:      out.redirect,
+					Headers:       out.headers,
+					Techs:         out.techs,
 				}
 
 				_ = rec.WriteFinding(fm)
 				e.emit("finding", fm)
+
+				if len(out.techs) > 0 {
+					hm := meta.Hosts[res.host.target]
+					var grew bool
+					if hm.Techs, grew = domain.MergeTechs(hm.Techs, out.techs); grew {
+						meta.Hosts[res.host.target] = hm
+						markDirty()
+						e.emit("host_techs", domain.HostTechsMsg{ScanID: scanID, Target: res.host.target, Techs: hm.Techs})
+					}
+				}
+
+				// Variants of a file-like finding are queued on the same host; they are not expanded again.
+				if len(req.VariantPatterns) > 0 && res.source != sourceVariant && res.technique == "" {
+					vs := variantPaths(res.path, req.VariantPatterns)
+					items := make([]harvested, 0, len(vs))
+					for _, v := range vs {
+						items = append(items, harvested{path: v, source: sourceVariant, parent: res.path})
+					}
+					if n := res.host.queueExtra(items); n > 0 {
+						res.host.total += int64(n)
+						hm := meta.Hosts[res.host.target]
+						hm.Total = res.host.total
+						meta.Hosts[res.host.target] = hm
+						meta.TotalRequests += int64(n)
+						meta.VariantProbes += int64(n)
+						markDirty()
+					}
+				}
+
+				if req.Bypass && isBypassCandidate(out.status, res.source) {
+					if n := res.host.queueExtra(bypassAttempts(res.path)); n > 0 {
+						res.host.total += int64(n)
+						hm := meta.Hosts[res.host.target]
+						hm.Total = res.host.total
+						meta.Hosts[res.host.target] = hm
+						meta.TotalRequests += int64(n)
+						meta.BypassProbes += int64(n)
+						markDirty()
+					}
+				}
 			}
 
 			pm := domain.Probe{
 				ScanID:      scanID,
 				Target:      res.host.target,
 				Path:        res.path,
+				Index:       res.index,
+				Source:      res.source,
+				Parent:      res.parent,
+				Method:      res.method,
+				Technique:   res.technique,
 				URL:         res.url,
 				Status:      out.status,
 				Length:      out.length,
 				DurationMs:  out.durMs,
 				ContentType: out.ct,
 				Location:    out.loc,
+				RemoteIP:    out.remoteIP,
+				Proto:       out.proto,
+				Truncated:   out.truncated,
 				Error:       out.errStr,
 				At:          res.at,
 			}
@@ -279,6 +401,8 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 				statusStr := domain.HostStatusCompleted
 				if stoppedNow {
 					statusStr = domain.HostStatusStopped
+				} else if res.host.halted() {
+					statusStr = res.host.controlState()
 				}
 
 				hm := meta.Hosts[res.host.target]
@@ -293,6 +417,8 @@ func (e *Engine) runScan(rt *runtime, req domain.StartRequest) {
 				meta.TotalErrors = totalErrors
 				markDirty()
 			}
+
+			res.host.jobDone()
 		}
 	}
 }
@@ -57,11 +57,12 @@ func soft404TestPaths(guid string) []string {
 func (e *Engine) computeSoft404Baselines(
 	ctx context.Context,
 	rt *runtime,
-	client *http.Client,
+	pr *prober,
 	timeout time.Duration,
 	lim limiters,
 	hosts []*hostCfg,
 	workers int,
+	spider bool,
 ) {
 	if len(hosts) == 0 {
 		return
@@ -83,7 +84,12 @@ func (e *Engine) computeSoft404Baselines(
 				if h == nil || h.base == nil {
 					continue
 				}
-				h.soft404 = e.calcSoft404Sig(ctx, rt, client, timeout, lim, h)
+				h.soft404 = e.calcSoft404Sig(ctx, rt, pr, timeout, lim, h)
+				var page []byte
+				h.landing, page = e.probeLanding(ctx, rt, pr, timeout, lim, h)
+				if spider {
+					h.extra = e.spiderHost(ctx, rt, pr, timeout, lim, h, page)
+				}
 			}
 		}()
 	}
@@ -105,7 +111,7 @@ func (e *Engine) computeSoft404Baselines(
 func (e *Engine) calcSoft404Sig(
 	ctx context.Context,
 	rt *runtime,
-	client *http.Client,
+	pr *prober,
 	timeout time.Duration,
 	lim limiters,
 	h *hostCfg,
@@ -120,29 +126,28 @@ func (e *Engine) calcSoft404Sig(
 		if rt != nil && !rt.waitIfPaused() {
 			return sig
 		}
+		if h.halted() {
+			return sig
+		}
 
-		if lim.rateTok != nil {
-			select {
-			case <-lim.rateTok:
-			case <-ctx.Done():
-				return sig
-			}
+		if !lim.rate.Wait(ctx) || !h.rate.Wait(ctx) {
+			return sig
 		}
 
-		select {
-		case h.sem <- struct{}{}:
-		case <-ctx.Done():
+		if !h.sem.Acquire(ctx) {
 			return sig
 		}
 
-		fullURL := buildRawURL(h.base, p)
-		out := performProbe(ctx, client, timeout, fullURL)
+		out := pr.probe(ctx, timeout, http.MethodGet, h.base, p, nil)
 
-		<-h.sem
+		h.sem.Release()
 
 		if out.wasCanceled {
 			return sig
 		}
+		if h.cert == nil && out.cert != nil {
+			h.cert = certInfo(out.cert)
+		}
 		if out.errStr == "" {
 			sig.Add(out.status, out.length)
 		}
@@ -0,0 +1,326 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// Probe sources for paths harvested by the spider pre-phase; wordlist paths have none.
+const (
+	sourceRobots  = "robots"
+	sourceSitemap = "sitemap"
+	sourceHTML    = "html"
+	sourceJS      = "js"
+	sourceWords   = "words"
+)
+
+const (
+	spiderMaxBody     = 2 << 20
+	spiderMaxEntries  = 2000
+	spiderMaxScripts  = 10
+	spiderMaxSitemaps = 5
+	spiderMaxWords    = 150
+)
+
+var (
+	reHTMLAttr   = regexp.MustCompile(`(?i)\b(?:href|src|action|data-src|data-url)\s*=\s*["']([^"'<>\s]+)["']`)
+	reScriptSrc  = regexp.MustCompile(`(?i)<script[^>]+src\s*=\s*["']([^"'<>\s]+)["']`)
+	reJSPath     = regexp.MustCompile("[\"'`](/[A-Za-z0-9_\\-./~%]{1,200}|https?://[^\"'`\\s]{1,300})[\"'`]")
+	reSitemapLoc = regexp.MustCompile(`(?i)<loc>\s*([^<\s]+)\s*</loc>`)
+	reHTMLDrop   = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>|<!--.*?-->`)
+	reHTMLTag    = regexp.MustCompile(`(?s)<[^>]*>`)
+	reWord       = regexp.MustCompile(`[A-Za-z][A-Za-z0-9_-]{2,29}`)
+)
+
+// harvested is a path fed ahead of the wordlist: a spider result, a finding variant or a bypass attempt.
+type harvested struct {
+	path   string
+	source string
+	parent string // finding path a variant or bypass came from
+
+	method    string
+	header    http.Header
+	technique string
+}
+
+// harvest collects same-host paths relative to the target base, first source wins.
+type harvest struct {
+	base  *url.URL
+	seen  map[string]struct{}
+	items []harvested
+}
+
+func newHarvest(base *url.URL) *harvest {
+	return &harvest{base: base, seen: map[string]struct{}{}}
+}
+
+func (hv *harvest) full() bool { return len(hv.items) >= spiderMaxEntries }
+
+// addRef resolves ref against page and keeps it, plus its parent directories, when it is on the target host.
+func (hv *harvest) addRef(page *url.URL, ref, source string) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return // mailto:, javascript:, data: ...
+	}
+	u = page.ResolveReference(u)
+	if !strings.EqualFold(u.Host, hv.base.Host) {
+		return
+	}
+
+	p := u.EscapedPath()
+	bp := strings.TrimRight(hv.base.EscapedPath(), "/")
+	if bp != "" {
+		if p != bp && !strings.HasPrefix(p, bp+"/") {
+			return // outside the target's base path
+		}
+		p = strings.TrimPrefix(p, bp)
+	}
+
+	segs := strings.Split(strings.Trim(p, "/"), "/")
+	for i := 1; i < len(segs); i++ {
+		hv.add("/"+strings.Join(segs[:i], "/"), source)
+	}
+	hv.add(strings.TrimRight(p, "/"), source)
+}
+
+func (hv *harvest) add(p, source string) {
+	if p == "" || p == "/" || hv.full() {
+		return
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	if _, ok := hv.seen[p]; ok {
+		return
+	}
+	hv.seen[p] = struct{}{}
+	hv.items = append(hv.items, harvested{path: p, source: source})
+}
+
+// spiderHost fetches the target's robots.txt, sitemaps and the scripts linked from the landing
+// page probeLanding already loaded (nil when it failed), and returns the paths and words found.
+func (e *Engine) spiderHost(
+	ctx context.Context,
+	rt *runtime,
+	pr *prober,
+	timeout time.Duration,
+	lim limiters,
+	h *hostCfg,
+	page []byte,
+) []harvested {
+	if h == nil || h.base == nil {
+		return nil
+	}
+
+	fetch := func(u *url.URL) (int, []byte, bool) {
+		if rt != nil && !rt.waitIfPaused() {
+			return 0, nil, false
+		}
+		if h.halted() || !lim.rate.Wait(ctx) || !h.rate.Wait(ctx) || !h.sem.Acquire(ctx) {
+			return 0, nil, false
+		}
+		out, body := pr.fetch(ctx, timeout, &url.URL{Scheme: u.Scheme, Host: u.Host}, u.RequestURI(), spiderMaxBody)
+		h.sem.Release()
+		if out.errStr != "" {
+			return 0, nil, !out.wasCanceled
+		}
+		return out.status, body, true
+	}
+
+	hv := newHarvest(h.base)
+	root := &url.URL{Scheme: h.base.Scheme, Host: h.base.Host, Path: "/"}
+
+	// robots.txt and the sitemaps it lists live at the host root.
+	sitemaps := []string{root.ResolveReference(&url.URL{Path: "/sitemap.xml"}).String()}
+	robotsURL := root.ResolveReference(&url.URL{Path: "/robots.txt"})
+	status, body, ok := fetch(robotsURL)
+	if !ok || status == 0 {
+		return hv.items // unreachable; the wordlist phase will report it
+	}
+	if status == http.StatusOK {
+		for _, line := range strings.Split(string(body), "\n") {
+			k, v, found := strings.Cut(line, ":")
+			if !found {
+				continue
+			}
+			v = strings.TrimSpace(v)
+			if i := strings.IndexByte(v, '#'); i >= 0 {
+				v = strings.TrimSpace(v[:i])
+			}
+			switch strings.ToLower(strings.TrimSpace(k)) {
+			case "allow", "disallow":
+				// Wildcard rules only contribute their literal prefix.
+				if i := strings.IndexAny(v, "*$"); i >= 0 {
+					v = v[:i]
+				}
+				hv.addRef(robotsURL, v, sourceRobots)
+			case "sitemap":
+				sitemaps = append(sitemaps, v)
+			}
+		}
+	}
+
+	fetched := map[string]bool{}
+	for i := 0; i < len(sitemaps) && len(fetched) < spiderMaxSitemaps && !hv.full(); i++ {
+		u, err := root.Parse(sitemaps[i])
+		if err != nil || fetched[u.String()] || !strings.EqualFold(u.Host, h.base.Host) {
+			continue
+		}
+		fetched[u.String()] = true
+
+		status, body, ok := fetch(u)
+		if !ok {
+			return hv.items
+		}
+		if status != http.StatusOK {
+			continue
+		}
+		for _, m := range reSitemapLoc.FindAllSubmatch(body, -1) {
+			loc := string(m[1])
+			if strings.HasSuffix(strings.ToLower(loc), ".xml") {
+				sitemaps = append(sitemaps, loc) // sitemap index
+			}
+			hv.addRef(u, loc, sourceSitemap)
+		}
+	}
+
+	// Landing page: links, scripts and words.
+	landing, _ := url.Parse(buildRawURL(h.base, "/"))
+	if page == nil || landing == nil {
+		return hv.items
+	}
+	for _, m := range reHTMLAttr.FindAllSubmatch(page, -1) {
+		hv.addRef(landing, string(m[1]), sourceHTML)
+	}
+
+	var scripts []*url.URL
+	for _, m := range reScriptSrc.FindAllSubmatch(page, -1) {
+		u, err := landing.Parse(string(m[1]))
+		if err == nil && strings.EqualFold(u.Host, h.base.Host) && len(scripts) < spiderMaxScripts {
+			scripts = append(scripts, u)
+		}
+	}
+
+	for _, w := range topWords(page, spiderMaxWords) {
+		hv.add("/"+w, sourceWords)
+	}
+
+	for _, u := range scripts {
+		if hv.full() {
+			break
+		}
+		status, js, ok := fetch(u)
+		if !ok {
+			break
+		}
+		if status != http.StatusOK {
+			continue
+		}
+		for _, m := range reJSPath.FindAllSubmatch(js, -1) {
+			hv.addRef(u, string(m[1]), sourceJS)
+		}
+	}
+
+	return hv.items
+}
+
+// topWords returns the most frequent words in the visible text of an HTML page, like cewl.
+func topWords(html []byte, n int) []string {
+	text := reHTMLDrop.ReplaceAll(html, []byte(" "))
+	text = reHTMLTag.ReplaceAll(text, []byte(" "))
+
+	counts := map[string]int{}
+	for _, w := range reWord.FindAll(text, -1) {
+		counts[string(w)]++
+	}
+
+	words := make([]string, 0, len(counts))
+	for w := range counts {
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > n {
+		words = words[:n]
+	}
+	return words
+}
+
+// dropWordlistDupes removes harvested paths the wordlist already contains, so each is probed once.
+// It reads the wordlist once for all hosts.
+func dropWordlistDupes(src domain.WordlistSource, lists ...*[]harvested) error {
+	want := map[string]struct{}{}
+	for _, l := range lists {
+		for _, it := range *l {
+			want[it.path] = struct{}{}
+		}
+	}
+	if len(want) == 0 {
+		return nil
+	}
+
+	dupes := map[string]struct{}{}
+	cur := src.Cursor()
+	for {
+		p, ok := cur.Next()
+		if !ok {
+			break
+		}
+		if _, ok := want[p]; ok {
+			dupes[p] = struct{}{}
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return err
+	}
+
+	for _, l := range lists {
+		kept := (*l)[:0]
+		for _, it := range *l {
+			if _, dup := dupes[it.path]; !dup {
+				kept = append(kept, it)
+			}
+		}
+		*l = kept
+	}
+	return nil
+}
+
+// countHarvest grows the host's total by its harvested paths. It must run before the host is fed.
+func (h *hostCfg) countHarvest() {
+	h.total += int64(len(h.extra))
+}
+
+// recordHarvest notes a host's harvested paths in meta and tells the UI about the new total.
+func (e *Engine) recordHarvest(scanID string, h *hostCfg, meta *domain.Meta) {
+	n := len(h.extra)
+	if n == 0 {
+		return
+	}
+
+	hm := meta.Hosts[h.target]
+	hm.Harvested = n
+	hm.Total = h.total
+	meta.Hosts[h.target] = hm
+	meta.TotalRequests += int64(n)
+
+	e.emit("host_harvested", domain.HostHarvestedMsg{ScanID: scanID, Target: h.target, Harvested: n, Total: h.total})
+}
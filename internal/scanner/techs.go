@@ -0,0 +1,204 @@
+package scanner
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+const (
+	techBodyPrefix    = 64 << 10 // body bytes fingerprint rules see
+	maxCapturedHeader = 256
+)
+
+type namedPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+type techMatcher struct {
+	name    string
+	headers []namedPattern
+	cookies []namedPattern
+	body    []*regexp.Regexp
+}
+
+// fingerprinter records a scan's capture headers and tags responses with technologies.
+type fingerprinter struct {
+	capture  []string
+	rules    []techMatcher
+	needBody bool
+}
+
+// newFingerprinter compiles rules; a rule with a pattern that does not compile (a hand-edited
+// rule file) is skipped rather than failing the scan.
+func newFingerprinter(rules []domain.TechRule, capture []string) *fingerprinter {
+	f := &fingerprinter{capture: capture}
+	for _, r := range rules {
+		m, ok := compileTechRule(r)
+		if !ok {
+			continue
+		}
+		if len(m.body) > 0 {
+			f.needBody = true
+		}
+		f.rules = append(f.rules, m)
+	}
+	return f
+}
+
+func compileTechRule(r domain.TechRule) (techMatcher, bool) {
+	m := techMatcher{name: r.Name}
+	for name, pat := range r.Headers {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return m, false
+		}
+		m.headers = append(m.headers, namedPattern{name: http.CanonicalHeaderKey(name), re: re})
+	}
+	for name, pat := range r.Cookies {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return m, false
+		}
+		m.cookies = append(m.cookies, namedPattern{name: name, re: re})
+	}
+	for _, pat := range r.Body {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return m, false
+		}
+		m.body = append(m.body, re)
+	}
+	return m, m.name != "" && (len(m.headers) > 0 || len(m.cookies) > 0 || len(m.body) > 0)
+}
+
+// wantsFingerprint reports whether a response with status could become a finding and is worth
+// capturing headers and a body prefix for.
+func wantsFingerprint(status int) bool {
+	return status != 0 && status != http.StatusNotFound && status != http.StatusTooManyRequests && status < 500
+}
+
+// headers returns the capture headers present on h. Set-Cookie is reduced to cookie names and
+// WWW-Authenticate to its scheme and realm, so no session values end up in findings.
+func (f *fingerprinter) headers(h http.Header, cookies []*http.Cookie) map[string]string {
+	var out map[string]string
+	for _, name := range f.capture {
+		var v string
+		switch http.CanonicalHeaderKey(name) {
+		case "Set-Cookie":
+			names := make([]string, 0, len(cookies))
+			for _, c := range cookies {
+				names = append(names, c.Name)
+			}
+			v = strings.Join(names, ", ")
+		case "Www-Authenticate":
+			challenges := make([]string, 0, 1)
+			for _, c := range h.Values(name) {
+				challenges = append(challenges, authChallenge(c))
+			}
+			v = strings.Join(challenges, ", ")
+		default:
+			v = strings.Join(h.Values(name), ", ")
+		}
+		if v == "" {
+			continue
+		}
+		if len(v) > maxCapturedHeader {
+			v = v[:maxCapturedHeader]
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[name] = v
+	}
+	return out
+}
+
+var reRealm = regexp.MustCompile(`(?i)\brealm\s*=\s*("[^"]*"|[^\s,]+)`)
+
+// authChallenge keeps the scheme and realm of a WWW-Authenticate challenge.
+func authChallenge(v string) string {
+	v = strings.TrimSpace(v)
+	scheme := v
+	if i := strings.IndexAny(v, " \t"); i >= 0 {
+		scheme = v[:i]
+	}
+	if m := reRealm.FindStringSubmatch(v); m != nil {
+		return scheme + " realm=" + m[1]
+	}
+	return scheme
+}
+
+// match returns the sorted names of the rules any of whose patterns hit.
+func (f *fingerprinter) match(h http.Header, cookies []*http.Cookie, body []byte) []string {
+	var techs []string
+	for _, m := range f.rules {
+		if m.matches(h, cookies, body) {
+			techs = append(techs, m.name)
+		}
+	}
+	sort.Strings(techs)
+	return techs
+}
+
+func (m techMatcher) matches(h http.Header, cookies []*http.Cookie, body []byte) bool {
+	for _, p := range m.headers {
+		for _, v := range h.Values(p.name) {
+			if p.re.MatchString(v) {
+				return true
+			}
+		}
+	}
+	for _, p := range m.cookies {
+		for _, c := range cookies {
+			if c.Name == p.name && p.re.MatchString(c.Value) {
+				return true
+			}
+		}
+	}
+	if len(body) > 0 {
+		for _, re := range m.body {
+			if re.Match(body) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// prefixBuffer keeps the first n bytes written to it.
+type prefixBuffer struct {
+	buf bytes.Buffer
+	n   int
+}
+
+func (p *prefixBuffer) Write(b []byte) (int, error) {
+	if room := p.n - p.buf.Len(); room > 0 {
+		if len(b) > room {
+			p.buf.Write(b[:room])
+		} else {
+			p.buf.Write(b)
+		}
+	}
+	return len(b), nil
+}
+
+// decodedPrefix undoes a gzip or deflate encoding on a body prefix as far as it goes; a cut-off
+// stream still yields what was decoded before the cut.
+func decodedPrefix(prefix []byte, encoding string, max int) []byte {
+	if len(prefix) == 0 {
+		return nil
+	}
+	dec, err := decoder(bytes.NewReader(prefix), encoding)
+	if err != nil {
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(dec, int64(max)))
+	return b
+}
@@ -0,0 +1,209 @@
+package scanner
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// liveControls holds the knobs /api/scans/tune can change while a scan runs.
+type liveControls struct {
+	timeoutNs int64 // atomic
+
+	mu       sync.Mutex
+	workers  int
+	targets  int
+	hostRate int
+	rate     *rateGate
+	hosts    []*hostCfg
+	pool     *workerPool
+	prober   *prober
+	closed   bool // feeder has finished; no more hosts can be added
+	extend   func(targets []string) ([]string, map[string]string)
+
+	// wake is signalled whenever a host's dispatch state changes.
+	wake chan struct{}
+}
+
+func newLiveControls(req domain.StartRequest) *liveControls {
+	c := &liveControls{
+		workers:  sanitizeWorkers(req.Concurrency),
+		targets:  len(req.Targets),
+		hostRate: req.HostRateLimit,
+		rate:     newRateGate(req.RateLimit),
+		wake:     make(chan struct{}, 1),
+	}
+	c.setTimeout(time.Duration(req.TimeoutMs) * time.Millisecond)
+	return c
+}
+
+func (c *liveControls) timeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.timeoutNs))
+}
+
+func (c *liveControls) setTimeout(d time.Duration) {
+	atomic.StoreInt64(&c.timeoutNs, int64(d))
+}
+
+func (c *liveControls) workerCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.workers
+}
+
+// attachHosts gives each host its own rate gate and sizes its semaphore for the current worker count.
+func (c *liveControls) attachHosts(hosts []*hostCfg) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	perHost := perHostCapFor(c.workers, c.targets)
+	for _, h := range hosts {
+		if h == nil {
+			continue
+		}
+		c.attachLocked(h, perHost)
+	}
+}
+
+// addHost registers a host on a running scan and rebalances per-host caps.
+// Returns false once the feeder has finished.
+func (c *liveControls) addHost(h *hostCfg) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+	c.targets++
+	perHost := perHostCapFor(c.workers, c.targets)
+	for _, other := range c.hosts {
+		other.sem.SetLimit(perHost)
+	}
+	c.attachLocked(h, perHost)
+	return true
+}
+
+func (c *liveControls) attachLocked(h *hostCfg, perHost int) {
+	h.rate = newRateGate(c.hostRate)
+	h.sem.SetLimit(perHost)
+	h.wake = c.wake
+	c.hosts = append(c.hosts, h)
+}
+
+// closeIfDrained marks the scan closed to new hosts if every host is drained.
+func (c *liveControls) closeIfDrained() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, h := range c.hosts {
+		if !h.drained() {
+			return false
+		}
+	}
+	c.closed = true
+	return true
+}
+
+func (c *liveControls) setExtender(fn func(targets []string) ([]string, map[string]string)) {
+	c.mu.Lock()
+	c.extend = fn
+	c.mu.Unlock()
+}
+
+func (c *liveControls) extender() func(targets []string) ([]string, map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	return c.extend
+}
+
+func (c *liveControls) hostsSnapshot() []*hostCfg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*hostCfg(nil), c.hosts...)
+}
+
+func (c *liveControls) host(target string) *hostCfg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, h := range c.hosts {
+		if h.target == target {
+			return h
+		}
+	}
+	return nil
+}
+
+func (c *liveControls) attachPool(p *workerPool) {
+	c.mu.Lock()
+	c.pool = p
+	c.mu.Unlock()
+}
+
+func (c *liveControls) attachProber(pr *prober) {
+	c.mu.Lock()
+	c.prober = pr
+	c.mu.Unlock()
+}
+
+func (c *liveControls) apply(t domain.ScanTuning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.TimeoutMs != nil {
+		c.setTimeout(time.Duration(*t.TimeoutMs) * time.Millisecond)
+	}
+	if t.RateLimit != nil {
+		c.rate.Set(*t.RateLimit)
+	}
+	if t.HostRateLimit != nil {
+		c.hostRate = *t.HostRateLimit
+		for _, h := range c.hosts {
+			h.rate.Set(c.hostRate)
+		}
+	}
+	if t.Concurrency != nil {
+		c.workers = sanitizeWorkers(*t.Concurrency)
+		perHost := perHostCapFor(c.workers, c.targets)
+		for _, h := range c.hosts {
+			h.sem.SetLimit(perHost)
+		}
+		if c.pool != nil {
+			c.pool.Resize(c.workers)
+		}
+		if c.prober != nil {
+			c.prober.grow(perHost)
+		}
+	}
+}
+
+func (c *liveControls) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, h := range c.hosts {
+		h.rate.Stop()
+	}
+}
+
+func applyTuning(meta *domain.Meta, adj domain.ScanAdjustment) {
+	if meta == nil {
+		return
+	}
+	if adj.Concurrency != nil {
+		meta.Concurrency = sanitizeWorkers(*adj.Concurrency)
+	}
+	if adj.RateLimit != nil {
+		meta.RateLimit = *adj.RateLimit
+	}
+	if adj.HostRateLimit != nil {
+		meta.HostRateLimit = *adj.HostRateLimit
+	}
+	if adj.TimeoutMs != nil {
+		meta.TimeoutMs = *adj.TimeoutMs
+	}
+	meta.Adjustments = append(meta.Adjustments, adj)
+}
@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+const sourceVariant = "variant"
+
+// variantPaths expands patterns for a file-like path (one whose last segment has an extension).
+// It returns nil for directories and extension-less paths.
+func variantPaths(p string, patterns []string) []string {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	slash := strings.LastIndexByte(p, '/')
+	dir, file := p[:slash+1], p[slash+1:]
+	dot := strings.LastIndexByte(file, '.')
+	if dot <= 0 || dot == len(file)-1 {
+		return nil
+	}
+	if dir == "" {
+		dir = "/"
+	}
+	name, ext := file[:dot], file[dot:]
+
+	r := strings.NewReplacer("{path}", dir+file, "{dir}", dir, "{file}", file, "{name}", name, "{ext}", ext)
+	out := make([]string, 0, len(patterns))
+	for _, pat := range patterns {
+		v := r.Replace(pat)
+		if v != p && v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// extraKey identifies an extra job; bypass attempts on one path differ by method and technique.
+func (it harvested) extraKey() string {
+	if it.technique == "" {
+		return it.path
+	}
+	return it.method + " " + it.path + " " + it.technique
+}
+
+// queueExtra appends paths to the host's extra jobs, skipping any queued before.
+// It returns how many were added; the caller grows h.total to match.
+func (h *hostCfg) queueExtra(items []harvested) int {
+	h.mu.Lock()
+	if h.state == domain.HostStatusStopped || h.state == domain.HostStatusSkipped {
+		h.mu.Unlock()
+		return 0
+	}
+	if h.extraSet == nil {
+		h.extraSet = make(map[string]struct{}, len(h.extra)+len(items))
+		for _, it := range h.extra {
+			h.extraSet[it.extraKey()] = struct{}{}
+		}
+	}
+	n := 0
+	for _, it := range items {
+		k := it.extraKey()
+		if _, dup := h.extraSet[k]; dup {
+			continue
+		}
+		h.extraSet[k] = struct{}{}
+		h.extra = append(h.extra, it)
+		n++
+	}
+	h.mu.Unlock()
+
+	if n > 0 {
+		h.signal()
+	}
+	return n
+}
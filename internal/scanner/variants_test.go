@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariantPaths(t *testing.T) {
+	patterns := []string{"{path}.bak", "{dir}.{file}.swp", "{dir}{name}.old", "{dir}{name}{ext}~", "{path}"}
+
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"/config.php", []string{"/config.php.bak", "/.config.php.swp", "/config.old", "/config.php~"}},
+		{"/app/db.inc.php", []string{"/app/db.inc.php.bak", "/app/.db.inc.php.swp", "/app/db.inc.old", "/app/db.inc.php~"}},
+		{"/a.php?x=1#top", []string{"/a.php.bak", "/.a.php.swp", "/a.old", "/a.php~"}},
+		{"web.config", []string{"/web.config.bak", "/.web.config.swp", "/web.old", "/web.config~", "/web.config"}},
+		{"/dir/", nil},
+		{"/noext", nil},
+		{"/.htaccess", nil},
+		{"/trailing.", nil},
+	}
+	for _, tt := range tests {
+		if got := variantPaths(tt.in, patterns); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("variantPaths(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExtraKey(t *testing.T) {
+	a := harvested{path: "/x", source: sourceVariant}
+	b := harvested{path: "/x", source: sourceBypass, method: "POST", technique: "method-post"}
+	c := harvested{path: "/x", source: sourceBypass, technique: "x-forwarded-for"}
+	if a.extraKey() == b.extraKey() || b.extraKey() == c.extraKey() || a.extraKey() == c.extraKey() {
+		t.Errorf("extra keys collide: %q %q %q", a.extraKey(), b.extraKey(), c.extraKey())
+	}
+}
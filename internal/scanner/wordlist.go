@@ -1,15 +1,30 @@
 package scanner
 
-import "context"
+import (
+	"context"
+	"errors"
 
-func (e *Engine) loadWordlist(ctx context.Context, wordlistID string) ([]string, []string, error) {
-	paths, err := e.wordlists.WordlistLines(ctx, wordlistID)
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// loadWordlist opens the wordlist for streaming and returns its entry count and display names.
+func (e *Engine) loadWordlist(ctx context.Context, wordlistID string) (domain.WordlistSource, int64, []string, error) {
+	src, err := e.wordlists.OpenWordlist(ctx, wordlistID)
 	if err != nil {
-		return nil, nil, err
+		return nil, 0, nil, err
+	}
+	m, err := e.wordlists.WordlistMeta(ctx, wordlistID)
+	if err != nil || m == nil {
+		_ = src.Close()
+		if err == nil {
+			err = errors.New("missing wordlist meta")
+		}
+		return nil, 0, nil, err
 	}
 	wlNames := []string{}
-	if m, metaErr := e.wordlists.WordlistMeta(ctx, wordlistID); metaErr == nil && m != nil && len(m.Names) > 0 {
-		wlNames = m.Names
+	if m.DisplayName != "" {
+		wlNames = append(wlNames, m.DisplayName)
 	}
-	return paths, wlNames, nil
+	wlNames = append(wlNames, m.Names...)
+	return src, m.Entries, wlNames, nil
 }
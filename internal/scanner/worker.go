@@ -2,92 +2,159 @@ package scanner
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"net/http"
 	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
 )
 
 type job struct {
-	host *hostCfg
-	path string
+	host   *hostCfg
+	path   string
+	index  int64  // position in the original wordlist; -1 for harvested paths
+	source string // spider source of a harvested path, "" for wordlist paths
+	parent string
+
+	// Bypass attempts may change the method and add headers; method "" means GET.
+	method    string
+	header    http.Header
+	technique string
 }
 
 type probeOutcome struct {
-	status    int
-	bodyBytes int64
-	length    int64
-	ct        string
-	loc       string
-	errStr    string
-	durMs     int64
+	status        int
+	bodyBytes     int64
+	length        int64
+	truncated     bool  // body read stopped at the scan's cap
+	contentLength int64 // -1 when not advertised
+	redirect      *domain.RedirectInfo
+	ct            string
+	loc           string
+	remoteIP      string
+	proto         string
+	errStr        string
+	durMs         int64
+	cert          *x509.Certificate // leaf certificate of a TLS connection
+	body          []byte            // kept body prefix, only for prober.fetch
+	headers       map[string]string // capture headers, for finding candidates
+	techs         []string
 
 	wasCanceled bool
 }
 
 type probeResult struct {
-	host *hostCfg
-	path string
-	url  string
-	out  probeOutcome
-	at   string
+	host      *hostCfg
+	path      string
+	index     int64
+	source    string
+	parent    string
+	method    string
+	technique string
+	requests  int // HTTP requests sent for the job
+	url       string
+	out       probeOutcome
+	at        string
 }
 
-func performProbe(ctx context.Context, client *http.Client, timeout time.Duration, fullURL string) probeOutcome {
+func performRequest(ctx context.Context, client *http.Client, timeout time.Duration, method, fullURL string, header http.Header, po probeOptions) (out probeOutcome) {
 	t0 := time.Now()
-	resp, cancel, reqErr := doGet(ctx, client, timeout, fullURL)
-
-	out := probeOutcome{
-		status:    0,
-		bodyBytes: -1,
-		length:    -1,
-		ct:        "",
-		loc:       "",
-		errStr:    "",
-		durMs:     0,
+	if po.compression {
+		header = header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("Accept-Encoding", po.acceptEncoding())
+	}
+	var ci connInfo
+	resp, cancel, reqErr := doReq(ctx, client, timeout, method, fullURL, header, &ci)
+
+	out = probeOutcome{
+		status:        0,
+		bodyBytes:     -1,
+		length:        -1,
+		contentLength: -1,
+		ct:            "",
+		loc:           "",
+		errStr:        "",
+		durMs:         0,
 	}
 
 	defer func() {
 		out.durMs = time.Since(t0).Milliseconds()
+		out.remoteIP = ci.remoteIP
 		if cancel != nil {
 			cancel()
 		}
 	}()
 
 	if reqErr != nil {
-		out.errStr = reqErr.Error()
-		if errors.Is(reqErr, context.Canceled) && ctx.Err() != nil {
-			out.wasCanceled = true
-		}
+		out.fail(ctx, reqErr)
 		if resp != nil && resp.Body != nil {
 			_ = resp.Body.Close()
 		}
 		return out
 	}
 
+	out.readResponse(resp, po)
+	return out
+}
+
+// readResponse fills the status, headers of interest and body size from resp and closes its body.
+// bodyBytes counts bytes received; length follows the scan's length mode.
+func (out *probeOutcome) readResponse(resp *http.Response, po probeOptions) {
 	out.status = resp.StatusCode
+	out.contentLength = resp.ContentLength
+	out.proto = protoLabel(resp)
 	out.ct = resp.Header.Get("Content-Type")
 	out.loc = resp.Header.Get("Location")
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		out.cert = resp.TLS.PeerCertificates[0]
+	}
 
-	if resp.Body != nil {
-		nRead, readErr := drainAndCount(resp.Body)
-		_ = resp.Body.Close()
-		if readErr != nil {
-			out.errStr = "body read: " + readErr.Error()
-		} else {
-			out.bodyBytes = nRead
+	if resp.Body == nil {
+		return
+	}
+	fingerprint := po.fp != nil && wantsFingerprint(resp.StatusCode)
+	keep := po.keepBody
+	if fingerprint && po.fp.needBody && keep < techBodyPrefix {
+		keep = techBodyPrefix
+	}
+	st, readErr := measureBody(resp.Body, resp.Header.Get("Content-Encoding"), po, keep)
+	_ = resp.Body.Close()
+	if readErr != nil {
+		out.errStr = "body read: " + readErr.Error()
+		return
+	}
+	wire := st.wire
+	if st.truncated {
+		// Trust the advertised size over the bytes read; without one, the cap is a lower bound.
+		out.truncated = true
+		if resp.ContentLength >= 0 {
+			wire = resp.ContentLength
 		}
 	}
+	out.bodyBytes = wire
+	out.length = lengthFor(resp, wire, st.decoded, po)
+	if po.keepBody > 0 {
+		out.body = st.prefix
+	}
 
-	out.length = out.bodyBytes
-	return out
+	if fingerprint {
+		cookies := resp.Cookies()
+		out.headers = po.fp.headers(resp.Header, cookies)
+		out.techs = po.fp.match(resp.Header, cookies, st.prefix)
+	}
 }
 
 func (e *Engine) workerLoop(
 	ctx context.Context,
 	scanID string,
-	client *http.Client,
-	timeout time.Duration,
+	p *prober,
+	ctl *liveControls,
 	lim limiters,
+	quit <-chan struct{},
 	jobs <-chan job,
 	results chan<- probeResult,
 ) {
@@ -96,6 +163,9 @@ func (e *Engine) workerLoop(
 		case <-ctx.Done():
 			return
 
+		case <-quit:
+			return
+
 		case j, ok := <-jobs:
 			if !ok {
 				return
@@ -104,37 +174,56 @@ func (e *Engine) workerLoop(
 				continue
 			}
 
-			select {
-			case j.host.sem <- struct{}{}:
-			case <-ctx.Done():
+			switch j.host.controlState() {
+			case domain.HostStatusPaused:
+				j.host.requeue(j)
+				continue
+			case domain.HostStatusStopped, domain.HostStatusSkipped:
+				j.host.jobDone()
+				continue
+			}
+
+			if !j.host.sem.Acquire(ctx) {
 				return
 			}
 
-			if lim.rateTok != nil {
-				select {
-				case <-lim.rateTok:
-				case <-ctx.Done():
-					<-j.host.sem
-					return
-				}
+			if !lim.rate.Wait(ctx) || !j.host.rate.Wait(ctx) {
+				j.host.sem.Release()
+				return
 			}
 
 			fullURL := buildRawURL(j.host.base, j.path)
+			// Each HEAD-first follow-up or redirect hop waits once more and is counted as a request.
+			sent := 1
+			wait := func() bool {
+				sent++
+				return lim.rate.Wait(ctx) && j.host.rate.Wait(ctx)
+			}
 
-			out := performProbe(ctx, client, timeout, fullURL)
+			out, method := p.probeJob(ctx, ctl.timeout(), j, wait)
+			if p.opts.maxRedirects > 0 && j.technique == "" && out.errStr == "" &&
+				isRedirect(out.status) && !j.host.soft404.Match(out.status, out.length) {
+				out.redirect = p.followRedirects(ctx, ctl.timeout(), j.host, fullURL, out, wait)
+			}
 			if out.wasCanceled {
-				<-j.host.sem
+				j.host.sem.Release()
 				return
 			}
 
-			<-j.host.sem
+			j.host.sem.Release()
 
 			res := probeResult{
-				host: j.host,
-				path: j.path,
-				url:  fullURL,
-				out:  out,
-				at:   time.Now().UTC().Format(time.RFC3339Nano),
+				host:      j.host,
+				path:      j.path,
+				index:     j.index,
+				source:    j.source,
+				parent:    j.parent,
+				method:    method,
+				technique: j.technique,
+				requests:  sent,
+				url:       fullURL,
+				out:       out,
+				at:        time.Now().UTC().Format(time.RFC3339Nano),
 			}
 
 			select {
@@ -145,3 +234,11 @@ func (e *Engine) workerLoop(
 		}
 	}
 }
+
+// fail records a transport error, flagging it as a cancellation when the scan context is done.
+func (out *probeOutcome) fail(ctx context.Context, err error) {
+	out.errStr = err.Error()
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+		out.wasCanceled = true
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+	"github.com/Pusher91/webtruder/internal/server/api"
+)
+
+type fingerprintsResp struct {
+	Builtin   []domain.TechRule `json:"builtin"`
+	Custom    []domain.TechRule `json:"custom"`
+	UpdatedAt string            `json:"updatedAt,omitempty"`
+}
+
+// fingerprintsAPI lists the built-in fingerprint rules and replaces the custom ones. Custom
+// rules apply to scans started after the change.
+func (s *Server) fingerprintsAPI(r *http.Request) (any, *api.APIError) {
+	switch r.Method {
+
+	case http.MethodGet:
+		rs, err := s.techRules.Load()
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to read fingerprint rules"},
+			}
+		}
+		return fingerprintsResp{Builtin: domain.DefaultTechRules, Custom: rs.Rules, UpdatedAt: rs.UpdatedAt}, nil
+
+	case http.MethodPost:
+		var rs domain.TechRuleSet
+		if apiErr := api.ReadJSON(r, &rs); apiErr != nil {
+			return nil, apiErr
+		}
+		if details := rs.NormalizeAndValidate(); len(details) > 0 {
+			return nil, api.ValidationError(details)
+		}
+
+		saved, err := s.techRules.Save(rs)
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to save fingerprint rules"},
+			}
+		}
+
+		resp := fingerprintsResp{Builtin: domain.DefaultTechRules, Custom: saved.Rules, UpdatedAt: saved.UpdatedAt}
+		s.emit("fingerprints_updated", resp)
+		return resp, nil
+
+	default:
+		return nil, &api.APIError{
+			Status: http.StatusMethodNotAllowed,
+			Err:    api.Error{Code: "method_not_allowed", Message: "method not allowed"},
+		}
+	}
+}
@@ -83,6 +83,15 @@ func localIPv4s() []string {
 	return out
 }
 
+func isLocalIPv4(ip string) bool {
+	for _, l := range localIPv4s() {
+		if l == ip {
+			return true
+		}
+	}
+	return false
+}
+
 func outboundLocalIPv4() string {
 	// Uses the kernel routing table to pick an egress interface/IP without sending packets.
 	c, err := net.DialTimeout("udp", "8.8.8.8:80", 750*time.Millisecond)
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/server/api"
+)
+
+type estimateScanReq struct {
+	Targets    []string `json:"targets"`
+	WordlistID string   `json:"wordlistId"`
+}
+
+type skippedTarget struct {
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+type estimateScanResp struct {
+	Entries  int64           `json:"entries"`
+	Targets  int             `json:"targets"`
+	Requests int64           `json:"requests"` // same basis as Meta.TotalRequests; baselines not included
+	Skipped  []skippedTarget `json:"skipped,omitempty"`
+}
+
+func (s *Server) estimateScanAPI(r *http.Request) (any, *api.APIError) {
+	var req estimateScanReq
+	if apiErr := api.ReadJSON(r, &req); apiErr != nil {
+		return nil, apiErr
+	}
+
+	id, apiErr := api.RequireSHA256(req.WordlistID, "wordlistId")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	m, err := s.wordlists.Meta(id)
+	if err != nil {
+		return nil, &api.APIError{
+			Status: http.StatusNotFound,
+			Err:    api.Error{Code: "not_found", Message: "wordlist not found"},
+		}
+	}
+
+	sc, err := s.scope.Load()
+	if err != nil {
+		return nil, &api.APIError{
+			Status: http.StatusInternalServerError,
+			Err:    api.Error{Code: "internal_error", Message: "failed to read scope"},
+		}
+	}
+
+	out := estimateScanResp{Entries: m.Entries}
+	for _, t := range req.Targets {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if reason := sc.Check(t); reason != "" {
+			out.Skipped = append(out.Skipped, skippedTarget{Target: t, Reason: reason})
+			continue
+		}
+		out.Targets++
+	}
+	out.Requests = out.Entries * int64(out.Targets)
+
+	return out, nil
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+	"github.com/Pusher91/webtruder/internal/scanner"
+	"github.com/Pusher91/webtruder/internal/server/api"
+)
+
+type hostControlBody struct {
+	ScanID string `json:"scanId"`
+	Target string `json:"target"`
+}
+
+type hostControlFn func(id, target string) (bool, error)
+
+func (s *Server) pauseHostAPI(r *http.Request) (any, *api.APIError) {
+	return s.hostControlAPI(r, s.engine.PauseHost, "host_paused", "paused")
+}
+
+func (s *Server) resumeHostAPI(r *http.Request) (any, *api.APIError) {
+	return s.hostControlAPI(r, s.engine.ResumeHost, "host_resumed", "resumed")
+}
+
+func (s *Server) stopHostAPI(r *http.Request) (any, *api.APIError) {
+	return s.hostControlAPI(r, s.engine.StopHost, "host_stopped", "stopped")
+}
+
+func (s *Server) skipHostAPI(r *http.Request) (any, *api.APIError) {
+	return s.hostControlAPI(r, s.engine.SkipHost, "host_skipped", "skipped")
+}
+
+func (s *Server) hostControlAPI(r *http.Request, fn hostControlFn, event, key string) (any, *api.APIError) {
+	var b hostControlBody
+	if apiErr := api.ReadJSON(r, &b); apiErr != nil {
+		return nil, apiErr
+	}
+	id, apiErr := api.RequireScanID(b.ScanID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	target := strings.TrimSpace(b.Target)
+	if target == "" {
+		return nil, api.ValidationError(map[string]string{"target": "required"})
+	}
+
+	active, err := fn(id, target)
+	if active {
+		switch {
+		case errors.Is(err, scanner.ErrHostNotFound):
+			return nil, &api.APIError{Status: http.StatusNotFound, Err: api.Error{Code: "not_found", Message: "host not found in scan"}}
+		case errors.Is(err, scanner.ErrHostHalted):
+			return nil, &api.APIError{Status: http.StatusConflict, Err: api.Error{Code: "conflict", Message: "host is already stopped or skipped"}}
+		}
+		s.emit(event, map[string]any{"scanId": id, "target": target})
+		return map[string]any{key: true}, nil
+	}
+
+	var meta domain.Meta
+	if err := s.scanRepo.ReadMeta(id, &meta); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &api.APIError{Status: http.StatusNotFound, Err: api.Error{Code: "not_found", Message: "scan not found"}}
+		}
+		return nil, &api.APIError{Status: http.StatusInternalServerError, Err: api.Error{Code: "internal_error", Message: "failed to read scan meta"}}
+	}
+
+	return nil, &api.APIError{
+		Status: http.StatusConflict,
+		Err:    api.Error{Code: "conflict", Message: "scan is not running"},
+	}
+}
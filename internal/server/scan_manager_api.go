@@ -107,7 +107,7 @@ func (s *Server) stopScanAPI(r *http.Request) (any, *api.APIError) {
 		if meta.Hosts != nil {
 			for k, h := range meta.Hosts {
 				hs := strings.ToLower(strings.TrimSpace(string(h.Status)))
-				if hs != string(domain.HostStatusCompleted) && hs != string(domain.HostStatusError) {
+				if hs != string(domain.HostStatusCompleted) && hs != string(domain.HostStatusError) && hs != string(domain.HostStatusSkipped) {
 					h.Status = domain.HostStatusStopped
 					if strings.TrimSpace(h.FinishedAt) == "" {
 						h.FinishedAt = now
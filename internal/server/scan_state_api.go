@@ -13,6 +13,9 @@ import (
 type scanStateResp struct {
 	Meta   domain.Meta `json:"meta"`
 	Active bool        `json:"active"`
+
+	// Groups are hosts sharing a favicon hash or landing title, e.g. the same appliance.
+	Groups []domain.HostGroup `json:"groups,omitempty"`
 }
 
 func (s *Server) scanStateAPI(r *http.Request) (any, *api.APIError) {
@@ -42,7 +45,7 @@ func (s *Server) scanStateAPI(r *http.Request) (any, *api.APIError) {
 			if meta.Hosts != nil {
 				for k, h := range meta.Hosts {
 					hs := strings.ToLower(strings.TrimSpace(string(h.Status)))
-					if hs != string(domain.HostStatusCompleted) && hs != string(domain.HostStatusError) {
+					if hs != string(domain.HostStatusCompleted) && hs != string(domain.HostStatusError) && hs != string(domain.HostStatusSkipped) {
 						h.Status = domain.HostStatusStopped
 						meta.Hosts[k] = h
 					}
@@ -51,5 +54,5 @@ func (s *Server) scanStateAPI(r *http.Request) (any, *api.APIError) {
 		}
 	}
 
-	return scanStateResp{Meta: meta, Active: active}, nil
+	return scanStateResp{Meta: meta, Active: active, Groups: meta.HostGroups()}, nil
 }
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+	"github.com/Pusher91/webtruder/internal/scanner"
+	"github.com/Pusher91/webtruder/internal/server/api"
+)
+
+type addTargetsBody struct {
+	ScanID  string   `json:"scanId"`
+	Targets []string `json:"targets"`
+}
+
+type addTargetsResp struct {
+	Added    []string          `json:"added"`
+	Rejected map[string]string `json:"rejected,omitempty"`
+}
+
+func (s *Server) addTargetsAPI(r *http.Request) (any, *api.APIError) {
+	var b addTargetsBody
+	if apiErr := api.ReadJSON(r, &b); apiErr != nil {
+		return nil, apiErr
+	}
+	id, apiErr := api.RequireScanID(b.ScanID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	targets := make([]string, 0, len(b.Targets))
+	for _, t := range b.Targets {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		u, err := url.Parse(t)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, api.ValidationError(map[string]string{"targets": "invalid target URL: " + t})
+		}
+		targets = append(targets, t)
+	}
+	if len(targets) == 0 {
+		return nil, api.ValidationError(map[string]string{"targets": "must contain at least one non-empty entry"})
+	}
+
+	if apiErr := s.checkScope(targets, nil); apiErr != nil {
+		return nil, apiErr
+	}
+
+	active, added, rejected, err := s.engine.AddTargets(id, targets)
+	if active {
+		if errors.Is(err, scanner.ErrScanNotReady) {
+			return nil, &api.APIError{Status: http.StatusConflict, Err: api.Error{Code: "conflict", Message: "scan is not accepting new targets"}}
+		}
+		if len(added) > 0 {
+			s.emit("scan_targets_added", map[string]any{"scanId": id, "targets": added})
+		}
+		return addTargetsResp{Added: added, Rejected: rejected}, nil
+	}
+
+	var meta domain.Meta
+	if err := s.scanRepo.ReadMeta(id, &meta); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &api.APIError{Status: http.StatusNotFound, Err: api.Error{Code: "not_found", Message: "scan not found"}}
+		}
+		return nil, &api.APIError{Status: http.StatusInternalServerError, Err: api.Error{Code: "internal_error", Message: "failed to read scan meta"}}
+	}
+
+	return nil, &api.APIError{
+		Status: http.StatusConflict,
+		Err:    api.Error{Code: "conflict", Message: "scan is not running"},
+	}
+}
+
+type targetSuggestionsResp struct {
+	ScanID      string                    `json:"scanId"`
+	Suggestions []domain.TargetSuggestion `json:"suggestions"`
+}
+
+// targetSuggestionsAPI lists certificate SAN hosts the scan does not cover yet, checked against
+// the current scope so the UI can offer the in-scope ones for /api/scans/targets/add.
+func (s *Server) targetSuggestionsAPI(r *http.Request) (any, *api.APIError) {
+	id, apiErr := api.RequireScanID(r.URL.Query().Get("scanId"))
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	var meta domain.Meta
+	if err := s.scanRepo.ReadMeta(id, &meta); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &api.APIError{Status: http.StatusNotFound, Err: api.Error{Code: "not_found", Message: "scan not found"}}
+		}
+		return nil, &api.APIError{Status: http.StatusInternalServerError, Err: api.Error{Code: "internal_error", Message: "failed to read scan meta"}}
+	}
+	sc, err := s.scope.Load()
+	if err != nil {
+		return nil, &api.APIError{Status: http.StatusInternalServerError, Err: api.Error{Code: "internal_error", Message: "failed to read scope"}}
+	}
+
+	return targetSuggestionsResp{ScanID: id, Suggestions: meta.SANSuggestions(sc)}, nil
+}
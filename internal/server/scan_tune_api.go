@@ -0,0 +1,38 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+	"github.com/Pusher91/webtruder/internal/server/api"
+)
+
+func (s *Server) tuneScanAPI(r *http.Request) (any, *api.APIError) {
+	var req domain.TuneRequest
+	if apiErr := api.ReadJSON(r, &req); apiErr != nil {
+		return nil, apiErr
+	}
+	if details := req.NormalizeAndValidate(); len(details) > 0 {
+		return nil, api.ValidationError(details)
+	}
+
+	if s.engine.Tune(req.ScanID, req.ScanTuning) {
+		s.emit("scan_tuned", req)
+		return map[string]any{"tuned": true}, nil
+	}
+
+	var meta domain.Meta
+	if err := s.scanRepo.ReadMeta(req.ScanID, &meta); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &api.APIError{Status: http.StatusNotFound, Err: api.Error{Code: "not_found", Message: "scan not found"}}
+		}
+		return nil, &api.APIError{Status: http.StatusInternalServerError, Err: api.Error{Code: "internal_error", Message: "failed to read scan meta"}}
+	}
+
+	return nil, &api.APIError{
+		Status: http.StatusConflict,
+		Err:    api.Error{Code: "conflict", Message: "scan is not running"},
+	}
+}
@@ -26,6 +26,7 @@ type scansListItem struct {
 	Verbose       bool     `json:"verbose,omitempty"`
 	LogFile       string   `json:"logFile,omitempty"`
 	Proxy         string   `json:"proxy,omitempty"`
+	SourceIP      string   `json:"sourceIP,omitempty"`
 	Active        bool     `json:"active"`
 }
 
@@ -92,6 +93,7 @@ func (s *Server) scansListAPI(r *http.Request) (any, *api.APIError) {
 			Verbose:       meta.Verbose,
 			LogFile:       meta.LogFile,
 			Proxy:         meta.Proxy,
+			SourceIP:      meta.SourceIP,
 			Active:        active,
 		})
 	}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+	"github.com/Pusher91/webtruder/internal/server/api"
+)
+
+// scopeAPI reads (GET) or replaces (POST) the persisted scope. A "*.example.com" domain rule
+// matches every subdomain and the apex example.com as well.
+func (s *Server) scopeAPI(r *http.Request) (any, *api.APIError) {
+	switch r.Method {
+
+	case http.MethodGet:
+		sc, err := s.scope.Load()
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to read scope"},
+			}
+		}
+		return sc, nil
+
+	case http.MethodPost:
+		var sc domain.Scope
+		if apiErr := api.ReadJSON(r, &sc); apiErr != nil {
+			return nil, apiErr
+		}
+		if details := sc.NormalizeAndValidate(); len(details) > 0 {
+			return nil, api.ValidationError(details)
+		}
+
+		saved, err := s.scope.Save(sc)
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to save scope"},
+			}
+		}
+
+		s.emit("scope_updated", saved)
+		return saved, nil
+
+	default:
+		return nil, &api.APIError{
+			Status: http.StatusMethodNotAllowed,
+			Err:    api.Error{Code: "method_not_allowed", Message: "method not allowed"},
+		}
+	}
+}
+
+// checkScope rejects targets and pinned IPs outside the persisted scope before anything is sent.
+func (s *Server) checkScope(targets []string, resolve map[string]string) *api.APIError {
+	sc, err := s.scope.Load()
+	if err != nil {
+		return &api.APIError{
+			Status: http.StatusInternalServerError,
+			Err:    api.Error{Code: "internal_error", Message: "failed to read scope"},
+		}
+	}
+	req := domain.StartRequest{Targets: targets, Resolve: resolve}
+	if details := req.CheckScope(sc); len(details) > 0 {
+		return api.ValidationError(details)
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"context"
 	"embed"
 	"io/fs"
 	"net/http"
 	"path/filepath"
+	"sync"
 
 	"github.com/Pusher91/webtruder/internal/domain"
 	"github.com/Pusher91/webtruder/internal/scanner"
@@ -20,6 +22,12 @@ type Server struct {
 	broker            *broker
 	wordlists         *store.WordlistStore
 	scanRepo          *store.ScanRepo
+	scope             *store.ScopeStore
+	importRoot        *store.ImportRoot
+	hits              *store.HitsStore
+	techRules         *store.TechRuleStore
+	hitsMu            sync.Mutex
+	stop              context.CancelFunc
 	engine            *scanner.Engine
 	publicIPv4Enabled bool
 }
@@ -41,12 +49,50 @@ func NewWithDataDir(dataDir string) *Server {
 		broker:    newBroker(),
 		wordlists: ws,
 		scanRepo:  store.NewScanRepo(dataDir, ss),
+		scope:     store.NewScopeStore(filepath.Join(dataDir, "scope.json")),
+		hits:      store.NewHitsStore(filepath.Join(dataDir, "hits.json")),
+		techRules: store.NewTechRuleStore(filepath.Join(dataDir, "fingerprints.json")),
 	}
 
-	s.engine = scanner.New(ws, s.scanRepo, s)
+	// SecLists is the common case on attack boxes; use it when present.
+	if root, err := store.NewImportRoot(defaultImportRoot); err == nil {
+		s.importRoot = root
+	}
+
+	s.engine = scanner.New(ws, s.scanRepo, s.scope, s.techRules, s.hits, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stop = cancel
+	go s.hitsRefreshLoop(ctx)
 	return s
 }
 
+// Close stops the server's background work, currently the learned-wordlist refresh.
+func (s *Server) Close() {
+	if s != nil && s.stop != nil {
+		s.stop()
+	}
+}
+
+const defaultImportRoot = "/usr/share/seclists"
+
+// SetWordlistImportRoot sets the server-side directory wordlists can be imported from; "" disables it.
+func (s *Server) SetWordlistImportRoot(dir string) error {
+	if s == nil {
+		return nil
+	}
+	if dir == "" {
+		s.importRoot = nil
+		return nil
+	}
+	root, err := store.NewImportRoot(dir)
+	if err != nil {
+		return err
+	}
+	s.importRoot = root
+	return nil
+}
+
 func (s *Server) SetPublicIPv4Enabled(v bool) {
 	if s != nil {
 		s.publicIPv4Enabled = v
@@ -62,10 +108,17 @@ func (s *Server) Routes() http.Handler {
 	mux.HandleFunc("/events", s.handleEvents)
 
 	handleAPIMethod(mux, "/api/scan/start", http.MethodPost, 2<<20, s.startScanAPI)
-	handleAPIMethod(mux, "/api/wordlists/upload", http.MethodPost, 50<<20, s.uploadWordlistAPI)
+	handleAPIMethod(mux, "/api/wordlists/upload", http.MethodPost, 0, s.uploadWordlistAPI)
 
-	mux.HandleFunc("/api/wordlists", api.Wrap(s.wordlistsAPI))
+	handleAPI(mux, "/api/wordlists", 1<<20, s.wordlistsAPI)
 	mux.HandleFunc("/api/wordlists/exists", api.WrapMethod(http.MethodGet, s.wordlistExistsAPI))
+	mux.HandleFunc("/api/wordlists/import/browse", api.WrapMethod(http.MethodGet, s.importBrowseAPI))
+	handleAPIMethod(mux, "/api/wordlists/import", http.MethodPost, 1<<20, s.importWordlistsAPI)
+	handleAPIMethod(mux, "/api/wordlists/derive", http.MethodPost, 1<<20, s.deriveWordlistAPI)
+	mux.HandleFunc("/api/wordlists/preview", api.WrapMethod(http.MethodGet, s.wordlistPreviewAPI))
+	mux.HandleFunc("/api/wordlists/search", api.WrapMethod(http.MethodGet, s.wordlistSearchAPI))
+	handleAPI(mux, "/api/wordlists/hits", 1<<20, s.hitsWordlistAPI)
+	handleAPIMethod(mux, "/api/scan/estimate", http.MethodPost, 2<<20, s.estimateScanAPI)
 
 	mux.HandleFunc("/api/scans", api.WrapMethod(http.MethodGet, s.scansListAPI))
 	mux.HandleFunc("/api/scans/state", api.WrapMethod(http.MethodGet, s.scanStateAPI))
@@ -76,10 +129,22 @@ func (s *Server) Routes() http.Handler {
 	mux.HandleFunc("/api/scans/pause", api.WrapMethod(http.MethodPost, s.pauseScanAPI))
 	mux.HandleFunc("/api/scans/resume", api.WrapMethod(http.MethodPost, s.resumeScanAPI))
 	mux.HandleFunc("/api/scans/stop", api.WrapMethod(http.MethodPost, s.stopScanAPI))
+	mux.HandleFunc("/api/scans/tune", api.WrapMethod(http.MethodPost, s.tuneScanAPI))
+
+	mux.HandleFunc("/api/scans/targets/add", api.WrapMethod(http.MethodPost, s.addTargetsAPI))
+	mux.HandleFunc("/api/scans/targets/suggestions", api.WrapMethod(http.MethodGet, s.targetSuggestionsAPI))
+
+	mux.HandleFunc("/api/scans/hosts/pause", api.WrapMethod(http.MethodPost, s.pauseHostAPI))
+	mux.HandleFunc("/api/scans/hosts/resume", api.WrapMethod(http.MethodPost, s.resumeHostAPI))
+	mux.HandleFunc("/api/scans/hosts/stop", api.WrapMethod(http.MethodPost, s.stopHostAPI))
+	mux.HandleFunc("/api/scans/hosts/skip", api.WrapMethod(http.MethodPost, s.skipHostAPI))
 	mux.HandleFunc("/api/scans/delete", api.WrapMethod(http.MethodPost, s.deleteScanAPI))
 
 	mux.HandleFunc("/api/netinfo", api.WrapMethod(http.MethodGet, s.netInfoAPI))
 
+	handleAPI(mux, "/api/scope", 1<<20, s.scopeAPI)
+	handleAPI(mux, "/api/fingerprints", 2<<20, s.fingerprintsAPI)
+
 	return mux
 }
 
@@ -92,6 +157,15 @@ func handleAPIMethod(mux *http.ServeMux, path, method string, maxBytes int64, h
 	})
 }
 
+func handleAPI(mux *http.ServeMux, path string, maxBytes int64, h api.Handler) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if maxBytes > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		}
+		api.Wrap(h)(w, r)
+	})
+}
+
 type startResp struct {
 	Accepted bool     `json:"accepted"`
 	Targets  int      `json:"targets"`
@@ -111,17 +185,44 @@ func (s *Server) startScanAPI(r *http.Request) (any, *api.APIError) {
 		return nil, api.ValidationError(details)
 	}
 
+	if apiErr := s.checkScope(req.Targets, req.Resolve); apiErr != nil {
+		return nil, apiErr
+	}
+
+	if req.SourceIP != "" && !isLocalIPv4(req.SourceIP) {
+		return nil, api.ValidationError(map[string]string{
+			"sourceIP": "not a local IPv4 address on this machine",
+		})
+	}
+
 	req.ScanID = domain.NewScanID()
 
 	s.emit("scan_start_requested", map[string]any{
-		"targets":     req.Targets,
-		"wordlistId":  req.WordlistID,
-		"concurrency": req.Concurrency,
-		"timeoutMs":   req.TimeoutMs,
-		"rateLimit":   req.RateLimit,
-		"tags":        req.Tags,
-		"verbose":     req.Verbose,
-		"proxy":       req.Proxy,
+		"targets":           req.Targets,
+		"wordlistId":        req.WordlistID,
+		"concurrency":       req.Concurrency,
+		"timeoutMs":         req.TimeoutMs,
+		"rateLimit":         req.RateLimit,
+		"hostRateLimit":     req.HostRateLimit,
+		"tags":              req.Tags,
+		"verbose":           req.Verbose,
+		"proxy":             req.Proxy,
+		"resolve":           req.Resolve,
+		"dnsServer":         req.DNSServer,
+		"sourceIP":          req.SourceIP,
+		"prioritizeHits":    req.PrioritizeHits,
+		"spider":            req.Spider,
+		"variantPatterns":   req.VariantPatterns,
+		"bypass":            req.Bypass,
+		"rawHttp":           req.RawHTTP,
+		"transport":         req.Transport,
+		"lengthMode":        req.LengthMode,
+		"compression":       req.Compression,
+		"maxBodyBytes":      req.MaxBodyBytes,
+		"headFirst":         req.HeadFirst,
+		"followRedirects":   req.FollowRedirects,
+		"redirectCrossHost": req.RedirectCrossHost,
+		"captureHeaders":    req.CaptureHeaders,
 	})
 
 	s.engine.Start(req)
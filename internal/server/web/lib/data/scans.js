@@ -66,6 +66,10 @@ export function createScansData(state, apiFetch) {
             s.total = Number(h.total || 0);
             s.findings = Number(h.findings || 0);
             s.errors = Number(h.errors || 0);
+            s.cert = h.cert || null;
+            s.title = h.title || "";
+            s.landingStatus = Number(h.landingStatus || 0);
+            s.faviconHash = h.faviconHash ?? null;
             s.percent = (s.total > 0) ? Math.floor((s.checked * 100) / s.total) : 0;
             s.rate = 0;
         }
@@ -1,4 +1,5 @@
 import { apiFetch } from "../api.js";
+import { onSSE } from "../sse/sse.js";
 
 const PROXY_KEY = "webtruder.proxy";
 const el = (id) => document.getElementById(id);
@@ -53,14 +54,59 @@ function setWordlistStatus(text, isError = false) {
     out.textContent = text;
 }
 
+let estimateTimer = null;
+
+function scheduleEstimate() {
+    clearTimeout(estimateTimer);
+    estimateTimer = setTimeout(() => { updateEstimate().catch(() => {}); }, 300);
+}
+
+async function updateEstimate() {
+    const out = el("scanEstimate");
+    if (!out) return;
+
+    const wordlistId = getSelectedWordlistId();
+    const targets = (el("targets")?.value || "").split("\n").map((x) => x.trim()).filter(Boolean);
+    if (!wordlistId || targets.length === 0) {
+        out.textContent = "";
+        return;
+    }
+
+    const d = unwrap(await apiFetch("/api/scan/estimate", { method: "POST", body: { targets, wordlistId } }));
+    const skipped = (d.skipped || []).length;
+    out.textContent = `~${Number(d.requests || 0).toLocaleString()} requests ` +
+        `(${Number(d.entries || 0).toLocaleString()} paths × ${d.targets || 0} targets)` +
+        (skipped ? `, ${skipped} out of scope` : "");
+}
+
+function formatMB(n) {
+    return `${(Number(n || 0) / (1024 * 1024)).toFixed(1)} MB`;
+}
+
 async function uploadWordlistOnce(file) {
     const fd = new FormData();
     fd.append("file", file, file.name);
 
-    const d = unwrap(await apiFetch("/api/wordlists/upload", { method: "POST", body: fd }));
-    const id = d.wordlistId || d.id;
-    if (!id) throw new Error("upload failed (missing wordlistId)");
-    return id;
+    // Server reports progress over SSE, keyed by uploadId.
+    const uploadId = Array.from(crypto.getRandomValues(new Uint8Array(16))).map((b) => b.toString(16).padStart(2, "0")).join("");
+    const unsubscribe = onSSE("wordlist_upload_progress", (m) => {
+        if (m.uploadId !== uploadId) return;
+        if (m.phase === "receiving") {
+            const pct = m.total > 0 ? ` (${Math.min(100, Math.floor((m.received * 100) / m.total))}%)` : "";
+            setWordlistStatus(`Uploading ${file.name}... ${formatMB(m.received)}${pct}`);
+        } else if (m.phase === "storing") {
+            setWordlistStatus(`Processing ${file.name}...`);
+        }
+    });
+
+    try {
+        const d = unwrap(await apiFetch(`/api/wordlists/upload?uploadId=${uploadId}`, { method: "POST", body: fd }));
+        const id = d.wordlistId || d.id;
+        if (!id) throw new Error("upload failed (missing wordlistId)");
+        return id;
+    } finally {
+        unsubscribe();
+    }
 }
 
 async function sha256Hex(file) {
@@ -120,11 +166,35 @@ async function startScan() {
         launchMsg.textContent = "";
 
         const verbose = !!el("verbose")?.checked;
+        const prioritizeHits = !!el("prioritizeHits")?.checked;
+        const spider = !!el("spider")?.checked;
+        const variants = !!el("variants")?.checked;
+        const bypass = !!el("bypass")?.checked;
+        const rawHttp = !!el("rawHttp")?.checked;
+        const lengthMode = el("lengthMode")?.value || "body";
+        const compression = !!el("compression")?.checked;
+        const headFirst = !!el("headFirst")?.checked;
         const proxy = (el("proxy")?.value || "").trim();
 
+        const nonNeg = (id) => {
+            const n = Number.parseInt(el(id)?.value ?? "", 10);
+            return Number.isFinite(n) && n > 0 ? n : 0;
+        };
+        const transport = {
+            httpVersion: el("httpVersion")?.value || "",
+            disableKeepAlive: !!el("disableKeepAlive")?.checked,
+            maxConnsPerHost: nonNeg("maxConnsPerHost"),
+            dialTimeoutMs: nonNeg("dialTimeoutMs"),
+            tlsTimeoutMs: nonNeg("tlsTimeoutMs"),
+        };
+        const maxBodyBytes = nonNeg("maxBodyBytes");
+        const followRedirects = nonNeg("followRedirects");
+        const redirectCrossHost = !!el("redirectCrossHost")?.checked;
+        const captureHeaders = (el("captureHeaders")?.value || "").split(/[,\s]+/g).map((h) => h.trim()).filter(Boolean);
+
         await apiFetch("/api/scan/start", {
             method: "POST",
-            body: { targets, wordlistId, concurrency, timeoutMs, rateLimit, tags, verbose, proxy },
+            body: { targets, wordlistId, concurrency, timeoutMs, rateLimit, tags, verbose, proxy, prioritizeHits, spider, variants, bypass, rawHttp, transport, lengthMode, compression, maxBodyBytes, headFirst, followRedirects, redirectCrossHost, captureHeaders },
         });
 
         launchMsg.className = "text-xs text-emerald-400";
@@ -165,6 +235,7 @@ function bindWordlistPicker() {
 
         try {
             await ensureWordlistId();
+            scheduleEstimate();
         } catch (e) {
             setWordlistStatus(e?.message || "wordlist upload failed", true);
         }
@@ -183,7 +254,8 @@ async function refreshWordlists() {
     for (const it of items) {
         const opt = document.createElement("option");
         opt.value = it.id;
-        opt.textContent = `${it.name} (${String(it.id).slice(0, 12)})`;
+        const entries = it.entries ? `, ${Number(it.entries).toLocaleString()} paths` : "";
+        opt.textContent = `${it.name} (${String(it.id).slice(0, 12)}${entries})`;
         sel.appendChild(opt);
     }
     sel.value = keep;
@@ -224,17 +296,29 @@ function bindExistingWordlists() {
 
         if (!confirm("Delete this wordlist from the server?")) return;
 
+        const url = `/api/wordlists?id=${encodeURIComponent(id)}`;
         try {
-            await apiFetch(`/api/wordlists?id=${encodeURIComponent(id)}`, { method: "DELETE" });
+            await apiFetch(url, { method: "DELETE" });
         } catch (err) {
-            setWordlistStatus(err?.message || "delete failed", true);
-            return;
+            if (err?.code !== "conflict") {
+                setWordlistStatus(err?.message || "delete failed", true);
+                return;
+            }
+            // Still referenced by existing scans: confirm again before forcing.
+            if (!confirm(`${err.message}. Delete it anyway?`)) return;
+            try {
+                await apiFetch(`${url}&force=1`, { method: "DELETE" });
+            } catch (err2) {
+                setWordlistStatus(err2?.message || "delete failed", true);
+                return;
+            }
         }
 
         sel.value = "";
         hidden.value = "";
         if (file) file.value = "";
         setWordlistStatus("Wordlist deleted.");
+        scheduleEstimate();
         refreshWordlists().catch(() => {});
     });
 
@@ -243,6 +327,7 @@ function bindExistingWordlists() {
         hidden.value = id;
 
         if (file) file.value = "";
+        scheduleEstimate();
 
         if (!id) {
             setWordlistStatus("No wordlist selected.");
@@ -267,6 +352,7 @@ export function bindLaunchForm() {
 
     bindWordlistPicker();
     bindExistingWordlists();
+    el("targets")?.addEventListener("input", scheduleEstimate);
 
     el("startBtn")?.addEventListener("click", (e) => {
         e.preventDefault();
@@ -1,7 +1,30 @@
 import { ensureServer, resetRuntimeState, addProbe } from "../state/mutations.js";
 
+// The app keeps a single /events stream; other modules subscribe to extra event types with onSSE.
+let source = null;
+const listeners = new Map();
+
+function dispatch(e) {
+    const m = JSON.parse(e.data || "{}");
+    for (const fn of listeners.get(e.type) || []) fn(m);
+}
+
+// onSSE calls fn with the parsed payload of every `type` event and returns an unsubscribe function.
+export function onSSE(type, fn) {
+    let set = listeners.get(type);
+    if (!set) {
+        set = new Set();
+        listeners.set(type, set);
+        source?.addEventListener(type, dispatch);
+    }
+    set.add(fn);
+    return () => set.delete(fn);
+}
+
 export function startSSE({ state, ui, data, onScanDone } = {}) {
     const es = new EventSource("/events");
+    source = es;
+    for (const type of listeners.keys()) es.addEventListener(type, dispatch);
 
     es.addEventListener("ready", () => ui.setConn("connected"));
 
@@ -67,6 +90,14 @@ export function startSSE({ state, ui, data, onScanDone } = {}) {
         ui.updateBadges();
     });
 
+    es.addEventListener("host_cert", (e) => {
+        const m = JSON.parse(e.data || "{}");
+        if (!m.target) return;
+        const s = ensureServer(state, m.target);
+        s.cert = m.cert || null;
+        ui.renderServersTable();
+    });
+
     es.addEventListener("host_progress", (e) => {
         const m = JSON.parse(e.data || "{}");
         if (!m.target) return;
@@ -361,10 +361,17 @@ export function createFindingsPanel(state) {
                 const url = f.url || `${f.target || ""}${f.path || ""}`;
                 const status = Number(f.status || 0);
                 const length = Number(f.length ?? -1);
+                const rd = f.redirect;
+                const redirect = rd
+                    ? ` <span class="text-[10px] text-slate-400" title="${escapeHtml(rd.finalUrl || "")}">&rarr; ${escapeHtml(String(rd.finalStatus || 0))} [${escapeHtml(rd.class || "")}]</span>`
+                    : "";
+                const hdrs = Object.entries(f.headers || {}).map(([k, v]) => `${k}: ${v}`).join("\n");
+                const techs = (f.techs || []).map((t) =>
+                    ` <span class="text-[10px] px-1 rounded bg-slate-800 text-slate-300">${escapeHtml(t)}</span>`).join("");
                 return `
 <tr class="bg-slate-950">
-  <td class="p-2 font-mono">${escapeHtml(url)}</td>
-  <td class="p-2">${escapeHtml(String(status))}</td>
+  <td class="p-2 font-mono"${hdrs ? ` title="${escapeHtml(hdrs)}"` : ""}>${escapeHtml(url)}${techs}</td>
+  <td class="p-2">${escapeHtml(String(status))}${redirect}</td>
   <td class="p-2">${escapeHtml(fmtBytes(length))}</td>
 </tr>
 `.trim();
@@ -26,8 +26,8 @@ export function createRequestLogPanel(state) {
 
         tbody.innerHTML = view.map((p) => `
 <tr class="bg-slate-950">
-  <td class="p-2 font-mono">${escapeHtml(p.url || `${p.target || ""}${p.path || ""}`)}</td>
-  <td class="p-2">${escapeHtml(String(p.status || 0))}</td>
+  <td class="p-2 font-mono">${escapeHtml(p.url || `${p.target || ""}${p.path || ""}`)}${p.source ? ` <span class="text-[10px] text-sky-400">[${escapeHtml(p.source)}]</span>` : ""}${p.technique ? ` <span class="text-[10px] text-amber-400">[${escapeHtml(p.technique)}${p.method ? ` ${escapeHtml(p.method)}` : ""}]</span>` : ""}</td>
+  <td class="p-2">${escapeHtml(String(p.status || 0))}${p.proto ? ` <span class="text-[10px] text-slate-500">${escapeHtml(p.proto)}</span>` : ""}</td>
   <td class="p-2">${escapeHtml(fmtBytes(p.length))}</td>
   <td class="p-2">${escapeHtml(String(p.durationMs || 0))}</td>
   <td class="p-2 text-xs ${p.error ? "text-red-400" : "text-slate-500"}">${escapeHtml(p.error || "")}</td>
@@ -69,6 +69,15 @@ export function createServersPanel(state) {
         if (el("runningMeta")) el("runningMeta").textContent = `${totalChecked}/${totalTotal} requests - ${sumRps} req/s`;
     }
 
+    function landingHtml(s) {
+        const parts = [];
+        if (s.landingStatus) parts.push(String(s.landingStatus));
+        if (s.title) parts.push(s.title);
+        if (s.faviconHash != null) parts.push(`favicon ${s.faviconHash}`);
+        if (!parts.length) return "";
+        return `<div class="text-xs text-slate-500 font-sans mt-1">${escapeHtml(parts.join(" · "))}</div>`;
+    }
+
     function serverRowHtml(s) {
         const status = s.status || "queued";
         const pct = s.percent || 0;
@@ -81,9 +90,14 @@ export function createServersPanel(state) {
             isSelected ? "bg-slate-800/30 outline outline-1 outline-slate-700" : "",
         ].filter(Boolean).join(" ");
 
+        const c = s.cert;
+        const certTitle = c
+            ? ` title="${escapeHtml(`Subject: ${c.subject}\nIssuer: ${c.issuer}\nValid: ${c.notBefore} - ${c.notAfter}\nSANs: ${(c.sans || []).join(", ")}`)}"`
+            : "";
+
         return `
 <tr class="${trClass}" data-target="${encodeURIComponent(s.target)}">
-  <td class="p-3 font-mono">${escapeHtml(s.target)}</td>
+  <td class="p-3 font-mono"${certTitle}>${escapeHtml(s.target)}${landingHtml(s)}</td>
   <td class="p-3">
     <span class="px-2 py-1 rounded bg-slate-950 border border-slate-800 text-xs text-slate-300">${escapeHtml(status)}</span>
   </td>
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+	"github.com/Pusher91/webtruder/internal/server/api"
+	"github.com/Pusher91/webtruder/internal/store"
+)
+
+type deriveWordlistResp struct {
+	WordlistID string                  `json:"wordlistId"`
+	Name       string                  `json:"name"`
+	Bytes      int64                   `json:"bytes"`
+	Entries    int64                   `json:"entries"`
+	Lineage    *domain.WordlistLineage `json:"lineage,omitempty"`
+}
+
+func (s *Server) deriveWordlistAPI(r *http.Request) (any, *api.APIError) {
+	var req domain.DeriveWordlistRequest
+	if apiErr := api.ReadJSON(r, &req); apiErr != nil {
+		return nil, apiErr
+	}
+	if details := req.NormalizeAndValidate(); len(details) > 0 {
+		return nil, api.ValidationError(details)
+	}
+
+	res, err := s.wordlists.Derive(req)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &api.APIError{
+				Status: http.StatusNotFound,
+				Err:    api.Error{Code: "not_found", Message: err.Error()},
+			}
+		}
+		if errors.Is(err, store.ErrStaleReference) {
+			return nil, &api.APIError{
+				Status: http.StatusConflict,
+				Err:    api.Error{Code: "conflict", Message: err.Error()},
+			}
+		}
+		return nil, &api.APIError{
+			Status: http.StatusInternalServerError,
+			Err:    api.Error{Code: "internal_error", Message: "failed to build wordlist"},
+		}
+	}
+
+	out := deriveWordlistResp{WordlistID: res.ID, Name: res.Name, Bytes: res.Bytes}
+	if m, err := s.wordlists.Meta(res.ID); err == nil {
+		out.Entries = m.Entries
+		out.Lineage = m.Lineage
+	}
+
+	s.emit("wordlist_derived", out)
+	return out, nil
+}
@@ -2,7 +2,6 @@ package server
 
 import (
 	"net/http"
-	"os"
 
 	"github.com/Pusher91/webtruder/internal/server/api"
 )
@@ -17,6 +16,5 @@ func (s *Server) wordlistExistsAPI(r *http.Request) (any, *api.APIError) {
 		return nil, apiErr
 	}
 
-	_, err := os.Stat(s.wordlists.ContentPath(id))
-	return existsResp{Exists: err == nil}, nil
+	return existsResp{Exists: s.wordlists.Exists(id)}, nil
 }
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+	"github.com/Pusher91/webtruder/internal/server/api"
+)
+
+const hitsWordlistName = "Learned hits"
+
+// hitsWordlistAPI: GET returns the learned-hits state, POST saves the config and rebuilds now.
+func (s *Server) hitsWordlistAPI(r *http.Request) (any, *api.APIError) {
+	switch r.Method {
+	case http.MethodGet:
+		st, err := s.hits.Load()
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to read hits state"},
+			}
+		}
+		return st, nil
+
+	case http.MethodPost:
+		var cfg domain.HitsConfig
+		if apiErr := api.ReadJSON(r, &cfg); apiErr != nil {
+			return nil, apiErr
+		}
+		if details := cfg.NormalizeAndValidate(); len(details) > 0 {
+			return nil, api.ValidationError(details)
+		}
+
+		st, err := s.rebuildHits(r.Context(), &cfg)
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to build hits wordlist"},
+			}
+		}
+		return st, nil
+
+	default:
+		return nil, &api.APIError{
+			Status: http.StatusMethodNotAllowed,
+			Err:    api.Error{Code: "method_not_allowed", Message: "method not allowed"},
+		}
+	}
+}
+
+// rebuildHits aggregates findings into a fresh wordlist and records the outcome.
+// A nil cfg reuses the persisted one. The previous list is removed once no scan references it,
+// unless ctx is already done.
+func (s *Server) rebuildHits(ctx context.Context, cfg *domain.HitsConfig) (*domain.HitsState, error) {
+	s.hitsMu.Lock()
+	defer s.hitsMu.Unlock()
+
+	st, err := s.hits.Load()
+	if err != nil {
+		return nil, err
+	}
+	if cfg != nil {
+		st.Config = *cfg
+	}
+	st.LastRunAt = time.Now().UTC().Format(time.RFC3339)
+	st.LastError = ""
+
+	hits, scans, err := s.scanRepo.PathHits(st.Config.HitsFilter)
+	if err != nil {
+		st.LastError = err.Error()
+		_ = s.hits.Save(*st)
+		return nil, err
+	}
+	st.Scans = scans
+
+	// Count how many hosts probed each path now, so scans ranking by hit rate need not re-read
+	// every past wordlist when they start.
+	hitScans, err := s.scanRepo.HitScans(st.Config.HitsFilter)
+	if err == nil {
+		err = s.wordlists.CountProbed(ctx, hits, hitScans)
+	}
+	if err == nil {
+		err = s.hits.SaveRates(hits)
+	}
+	if err != nil {
+		st.LastError = err.Error()
+		_ = s.hits.Save(*st)
+		return nil, err
+	}
+
+	if len(hits) == 0 {
+		st.LastError = "no findings matched the filter"
+		if err := s.hits.Save(*st); err != nil {
+			return nil, err
+		}
+		s.emit("hits_wordlist_updated", st)
+		return st, nil
+	}
+
+	var b strings.Builder
+	for _, h := range hits {
+		b.WriteString(h.Path)
+		b.WriteByte('\n')
+	}
+	id, _, err := s.wordlists.Put("hits.txt", strings.NewReader(b.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	name := hitsWordlistName
+	desc := strconv.Itoa(len(hits)) + " paths from " + strconv.Itoa(scans) + " scans, ranked by distinct hosts"
+	tags := []string{"hits"}
+	if _, err := s.wordlists.Update(domain.UpdateWordlistRequest{ID: id, DisplayName: &name, Description: &desc, Tags: &tags}); err != nil {
+		return nil, err
+	}
+
+	prev := st.WordlistID
+	st.WordlistID = id
+	st.Entries = int64(len(hits))
+	if err := s.hits.Save(*st); err != nil {
+		return nil, err
+	}
+
+	if prev != "" && prev != id && ctx.Err() == nil {
+		if used, err := s.scanRepo.ScansUsingWordlist(prev); err == nil && len(used) == 0 {
+			_, _ = s.wordlists.Delete(prev)
+		}
+	}
+
+	s.emit("hits_wordlist_updated", st)
+	return st, nil
+}
+
+// hitsRefreshLoop rebuilds the learned list whenever its refresh interval has elapsed, until ctx is done.
+func (s *Server) hitsRefreshLoop(ctx context.Context) {
+	t := time.NewTicker(time.Minute)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		st, err := s.hits.Load()
+		if err != nil || st.Config.RefreshMinutes <= 0 {
+			continue
+		}
+		last, err := time.Parse(time.RFC3339, st.LastRunAt)
+		if err == nil && time.Since(last) < time.Duration(st.Config.RefreshMinutes)*time.Minute {
+			continue
+		}
+		_, _ = s.rebuildHits(ctx, nil)
+	}
+}
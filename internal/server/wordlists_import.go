@@ -0,0 +1,127 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/server/api"
+	"github.com/Pusher91/webtruder/internal/store"
+)
+
+type importBrowseResp struct {
+	Root  string              `json:"root"`
+	Path  string              `json:"path"`
+	Items []store.ImportEntry `json:"items"`
+}
+
+type importWordlistsReq struct {
+	Paths []string `json:"paths"`
+	Mode  string   `json:"mode"` // "copy" (default) or "reference"
+}
+
+type importWordlistsResp struct {
+	Items []store.PutResult `json:"items"`
+}
+
+func errImportDisabled() *api.APIError {
+	return &api.APIError{
+		Status: http.StatusConflict,
+		Err:    api.Error{Code: "conflict", Message: "wordlist import root is not configured"},
+	}
+}
+
+func (s *Server) importBrowseAPI(r *http.Request) (any, *api.APIError) {
+	if s.importRoot == nil {
+		return nil, errImportDisabled()
+	}
+
+	rel := strings.TrimSpace(r.URL.Query().Get("path"))
+	items, err := s.importRoot.List(rel)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &api.APIError{Status: http.StatusNotFound, Err: api.Error{Code: "not_found", Message: "directory not found"}}
+		}
+		return nil, &api.APIError{Status: http.StatusInternalServerError, Err: api.Error{Code: "internal_error", Message: "failed to list directory"}}
+	}
+
+	return importBrowseResp{Root: s.importRoot.Dir(), Path: rel, Items: items}, nil
+}
+
+func (s *Server) importWordlistsAPI(r *http.Request) (any, *api.APIError) {
+	if s.importRoot == nil {
+		return nil, errImportDisabled()
+	}
+
+	var req importWordlistsReq
+	if apiErr := api.ReadJSON(r, &req); apiErr != nil {
+		return nil, apiErr
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	if mode == "" {
+		mode = "copy"
+	}
+	if mode != "copy" && mode != "reference" {
+		return nil, api.ValidationError(map[string]string{"mode": "must be copy or reference"})
+	}
+	if len(req.Paths) == 0 {
+		return nil, api.ValidationError(map[string]string{"paths": "required"})
+	}
+
+	abs := make([]string, 0, len(req.Paths))
+	for _, p := range req.Paths {
+		a, ok := s.importRoot.Resolve(p)
+		if !ok {
+			return nil, api.ValidationError(map[string]string{"paths": "not inside the import root: " + p})
+		}
+		if st, err := os.Stat(a); err != nil || !st.Mode().IsRegular() {
+			return nil, api.ValidationError(map[string]string{"paths": "not a regular file: " + p})
+		}
+		if mode == "reference" && store.IsArchiveName(a) {
+			return nil, api.ValidationError(map[string]string{"paths": "archives can only be imported by copy: " + p})
+		}
+		abs = append(abs, a)
+	}
+
+	items := make([]store.PutResult, 0, len(abs))
+	for _, a := range abs {
+		var res []store.PutResult
+		var err error
+		if mode == "reference" {
+			var id string
+			var n int64
+			id, n, err = s.wordlists.Reference(a)
+			res = []store.PutResult{{ID: id, Name: baseName(a), Bytes: n}}
+		} else {
+			res, err = s.importCopy(a)
+		}
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to import " + baseName(a)},
+			}
+		}
+		items = append(items, res...)
+	}
+
+	s.emit("wordlists_imported", importWordlistsResp{Items: items})
+	return importWordlistsResp{Items: items}, nil
+}
+
+func (s *Server) importCopy(abs string) ([]store.PutResult, error) {
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return s.wordlists.PutUpload(baseName(abs), f)
+}
+
+func baseName(p string) string {
+	if i := strings.LastIndexAny(p, `/\`); i >= 0 {
+		return p[i+1:]
+	}
+	return p
+}
@@ -1,16 +1,29 @@
 package server
 
 import (
+	"errors"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/domain"
 
 	"github.com/Pusher91/webtruder/internal/server/api"
 )
 
 type wordlistItem struct {
-	ID         string `json:"id"`
-	Name       string `json:"name"`
-	Bytes      int64  `json:"bytes"`
-	UploadedAt string `json:"uploadedAt"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Names       []string `json:"names,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Bytes       int64    `json:"bytes"`
+	Entries     int64    `json:"entries"`
+	UploadedAt  string   `json:"uploadedAt"`
+
+	Lineage *domain.WordlistLineage `json:"lineage,omitempty"`
+	Stats   *domain.WordlistStats   `json:"stats,omitempty"`
 }
 
 type listWordlistsResp struct {
@@ -18,7 +31,50 @@ type listWordlistsResp struct {
 }
 
 type deleteWordlistResp struct {
-	Deleted bool `json:"deleted"`
+	Deleted bool     `json:"deleted"`
+	UsedBy  []string `json:"usedBy,omitempty"` // scans that referenced the deleted list
+}
+
+func wordlistDisplayName(m domain.WordlistMeta) string {
+	if m.DisplayName != "" {
+		return m.DisplayName
+	}
+	if len(m.Names) > 0 && m.Names[0] != "" {
+		return m.Names[0]
+	}
+	return m.ID[:12] + ".txt"
+}
+
+func toWordlistItem(m domain.WordlistMeta) wordlistItem {
+	return wordlistItem{
+		ID:          m.ID,
+		Name:        wordlistDisplayName(m),
+		Names:       m.Names,
+		Description: m.Description,
+		Tags:        m.Tags,
+		Bytes:       m.Bytes,
+		Entries:     m.Entries,
+		UploadedAt:  m.UploadedAt,
+		Lineage:     m.Lineage,
+		Stats:       m.Stats,
+	}
+}
+
+// hasAllTags reports whether have contains every tag in want.
+func hasAllTags(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if h == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *Server) wordlistsAPI(r *http.Request) (any, *api.APIError) {
@@ -33,28 +89,77 @@ func (s *Server) wordlistsAPI(r *http.Request) (any, *api.APIError) {
 			}
 		}
 
+		// ?tag=api&tag=iis or ?tag=api,iis keeps lists carrying all of them.
+		var want []string
+		for _, t := range r.URL.Query()["tag"] {
+			want = append(want, strings.Split(t, ",")...)
+		}
+		want = domain.NormalizeWordlistTags(want)
+
 		items := make([]wordlistItem, 0, len(metas))
 		for _, m := range metas {
-			name := m.ID[:12] + ".txt"
-			if len(m.Names) > 0 && m.Names[0] != "" {
-				name = m.Names[0]
+			if !hasAllTags(m.Tags, want) {
+				continue
 			}
-			items = append(items, wordlistItem{
-				ID:         m.ID,
-				Name:       name,
-				Bytes:      m.Bytes,
-				UploadedAt: m.UploadedAt,
-			})
+			items = append(items, toWordlistItem(m))
 		}
 
 		return listWordlistsResp{Items: items}, nil
 
+	case http.MethodPatch:
+		var req domain.UpdateWordlistRequest
+		if apiErr := api.ReadJSON(r, &req); apiErr != nil {
+			return nil, apiErr
+		}
+		if details := req.NormalizeAndValidate(); len(details) > 0 {
+			return nil, api.ValidationError(details)
+		}
+
+		m, err := s.wordlists.Update(req)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, &api.APIError{
+					Status: http.StatusNotFound,
+					Err:    api.Error{Code: "not_found", Message: "wordlist not found"},
+				}
+			}
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to update wordlist"},
+			}
+		}
+
+		item := toWordlistItem(*m)
+		s.emit("wordlist_updated", item)
+		return item, nil
+
 	case http.MethodDelete:
 		id, apiErr := api.RequireSHA256(r.URL.Query().Get("id"), "id")
 		if apiErr != nil {
 			return nil, apiErr
 		}
 
+		// Scans keep only the ID, so deleting a list they used breaks re-running them.
+		// Without ?force=1 that is refused with the scan IDs so the UI can confirm.
+		usedBy, err := s.scanRepo.ScansUsingWordlist(id)
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err:    api.Error{Code: "internal_error", Message: "failed to check wordlist usage"},
+			}
+		}
+		force := r.URL.Query().Get("force")
+		if len(usedBy) > 0 && force != "1" && force != "true" {
+			return nil, &api.APIError{
+				Status: http.StatusConflict,
+				Err: api.Error{
+					Code:    "conflict",
+					Message: "wordlist is used by " + strconv.Itoa(len(usedBy)) + " scan(s)",
+					Details: map[string]string{"scans": strings.Join(usedBy, ",")},
+				},
+			}
+		}
+
 		deleted, err := s.wordlists.Delete(id)
 		if err != nil {
 			return nil, &api.APIError{
@@ -63,7 +168,7 @@ func (s *Server) wordlistsAPI(r *http.Request) (any, *api.APIError) {
 			}
 		}
 
-		return deleteWordlistResp{Deleted: deleted}, nil
+		return deleteWordlistResp{Deleted: deleted, UsedBy: usedBy}, nil
 
 	default:
 		return nil, &api.APIError{
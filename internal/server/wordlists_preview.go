@@ -0,0 +1,92 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/server/api"
+	"github.com/Pusher91/webtruder/internal/store"
+)
+
+type wordlistPageResp struct {
+	Items      []string `json:"items"`
+	NextCursor int64    `json:"nextCursor"`
+	HasMore    bool     `json:"hasMore"`
+	Entries    int64    `json:"entries"`
+}
+
+// wordlistPreviewAPI pages through the normalised entries scans will request.
+func (s *Server) wordlistPreviewAPI(r *http.Request) (any, *api.APIError) {
+	return s.wordlistPage(r, nil)
+}
+
+// wordlistSearchAPI pages through entries containing q (case-insensitive), or matching it with regex=1.
+// Each call scans a bounded slice of the list; callers follow nextCursor while hasMore is set.
+func (s *Server) wordlistSearchAPI(r *http.Request) (any, *api.APIError) {
+	q := r.URL.Query()
+	needle := strings.TrimSpace(q.Get("q"))
+	if needle == "" {
+		return nil, api.ValidationError(map[string]string{"q": "required"})
+	}
+
+	var match func(string) bool
+	if q.Get("regex") == "1" || q.Get("regex") == "true" {
+		re, err := regexp.Compile(needle)
+		if err != nil {
+			return nil, api.ValidationError(map[string]string{"q": "invalid regex"})
+		}
+		match = re.MatchString
+	} else {
+		needle = strings.ToLower(needle)
+		match = func(line string) bool { return strings.Contains(strings.ToLower(line), needle) }
+	}
+
+	return s.wordlistPage(r, match)
+}
+
+func (s *Server) wordlistPage(r *http.Request, match func(string) bool) (any, *api.APIError) {
+	q := r.URL.Query()
+
+	id, apiErr := api.RequireSHA256(q.Get("id"), "id")
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	cursor, limit, apiErr := api.CursorLimitFromQuery(q, 200, 2000)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	m, err := s.wordlists.Meta(id)
+	if err != nil || !s.wordlists.Exists(id) {
+		return nil, &api.APIError{
+			Status: http.StatusNotFound,
+			Err:    api.Error{Code: "not_found", Message: "wordlist not found"},
+		}
+	}
+
+	items, next, hasMore, err := s.wordlists.PathsPage(id, cursor, limit, match)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &api.APIError{
+				Status: http.StatusNotFound,
+				Err:    api.Error{Code: "not_found", Message: "wordlist not found"},
+			}
+		}
+		if errors.Is(err, store.ErrStaleReference) {
+			return nil, &api.APIError{
+				Status: http.StatusConflict,
+				Err:    api.Error{Code: "conflict", Message: err.Error()},
+			}
+		}
+		return nil, &api.APIError{
+			Status: http.StatusInternalServerError,
+			Err:    api.Error{Code: "internal_error", Message: "failed to read wordlist"},
+		}
+	}
+
+	return wordlistPageResp{Items: items, NextCursor: next, HasMore: hasMore, Entries: m.Entries}, nil
+}
@@ -1,18 +1,38 @@
 package server
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/Pusher91/webtruder/internal/domain"
 	"github.com/Pusher91/webtruder/internal/server/api"
+	"github.com/Pusher91/webtruder/internal/store"
 )
 
 type uploadWordlistResp struct {
-	WordlistID string `json:"wordlistId"`
-	Bytes      int64  `json:"bytes"`
+	WordlistID string            `json:"wordlistId"`
+	Bytes      int64             `json:"bytes"`
+	Items      []store.PutResult `json:"items"`
 }
 
+type uploadProgressMsg struct {
+	UploadID string `json:"uploadId"`
+	Name     string `json:"name"`
+	Phase    string `json:"phase"` // receiving, storing, done, error
+	Received int64  `json:"received"`
+	Total    int64  `json:"total,omitempty"` // request Content-Length when known
+}
+
+// uploadWordlistAPI streams the multipart "file" part straight into the store without buffering
+// the form, so there is no size cap. Progress is published as wordlist_upload_progress SSE events
+// keyed by the optional ?uploadId= query parameter.
 func (s *Server) uploadWordlistAPI(r *http.Request) (any, *api.APIError) {
-	if err := r.ParseMultipartForm(50 << 20); err != nil {
+	mr, err := r.MultipartReader()
+	if err != nil {
 		return nil, &api.APIError{
 			Status: http.StatusBadRequest,
 			Err: api.Error{
@@ -22,31 +42,103 @@ func (s *Server) uploadWordlistAPI(r *http.Request) (any, *api.APIError) {
 		}
 	}
 
-	f, hdr, err := r.FormFile("file")
-	if err != nil {
-		return nil, &api.APIError{
-			Status: http.StatusBadRequest,
-			Err: api.Error{
-				Code:    "validation_error",
-				Message: "invalid request",
-				Details: map[string]string{"file": "required"},
-			},
-		}
+	uploadID := strings.TrimSpace(r.URL.Query().Get("uploadId"))
+	if uploadID == "" {
+		uploadID = domain.NewScanID()
 	}
-	defer f.Close()
 
-	_ = hdr // available if you want to validate filename/extension/content-type
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, &api.APIError{
+				Status: http.StatusBadRequest,
+				Err:    api.Error{Code: "bad_request", Message: "invalid multipart form"},
+			}
+		}
+		if part.FormName() != "file" {
+			_ = part.Close()
+			continue
+		}
 
-	id, n, err := s.wordlists.Put(hdr.Filename, f)
-	if err != nil {
-		return nil, &api.APIError{
-			Status: http.StatusInternalServerError,
-			Err: api.Error{
-				Code:    "internal_error",
-				Message: "upload failed",
-			},
+		name := part.FileName()
+		pr := &progressReader{
+			r:     part,
+			every: 250 * time.Millisecond,
+			msg:   uploadProgressMsg{UploadID: uploadID, Name: name, Phase: "receiving", Total: r.ContentLength},
+			emit:  func(m uploadProgressMsg) { s.emit("wordlist_upload_progress", m) },
 		}
+
+		items, err := s.wordlists.PutUpload(name, pr)
+		_ = part.Close()
+		if err != nil {
+			pr.finish("error")
+			return nil, &api.APIError{
+				Status: http.StatusInternalServerError,
+				Err: api.Error{
+					Code:    "internal_error",
+					Message: "upload failed",
+				},
+			}
+		}
+		if len(items) == 0 {
+			pr.finish("error")
+			return nil, api.ValidationError(map[string]string{"file": "archive contains no files"})
+		}
+		pr.finish("done")
+
+		return uploadWordlistResp{WordlistID: items[0].ID, Bytes: items[0].Bytes, Items: items}, nil
+	}
+
+	return nil, &api.APIError{
+		Status: http.StatusBadRequest,
+		Err: api.Error{
+			Code:    "validation_error",
+			Message: "invalid request",
+			Details: map[string]string{"file": "required"},
+		},
+	}
+}
+
+// progressReader counts bytes read and emits a throttled progress message.
+type progressReader struct {
+	r     io.Reader
+	every time.Duration
+	emit  func(uploadProgressMsg)
+
+	mu   sync.Mutex
+	msg  uploadProgressMsg
+	last time.Time
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+
+	p.mu.Lock()
+	p.msg.Received += int64(n)
+	if errors.Is(err, io.EOF) {
+		p.msg.Phase = "storing"
 	}
+	now := time.Now()
+	send := now.Sub(p.last) >= p.every || errors.Is(err, io.EOF)
+	msg := p.msg
+	if send {
+		p.last = now
+	}
+	p.mu.Unlock()
+
+	if send {
+		p.emit(msg)
+	}
+	return n, err
+}
 
-	return uploadWordlistResp{WordlistID: id, Bytes: n}, nil
+func (p *progressReader) finish(phase string) {
+	p.mu.Lock()
+	p.msg.Phase = phase
+	msg := p.msg
+	p.mu.Unlock()
+	p.emit(msg)
 }
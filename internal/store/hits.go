@@ -0,0 +1,296 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// PathHits aggregates findings across stored scans matching f and ranks paths by the number of
+// distinct hosts they were found on. scans is how many scans contributed.
+func (r *ScanRepo) PathHits(f domain.HitsFilter) (hits []domain.PathHit, scans int, err error) {
+	hosts := map[string]map[string]struct{}{}
+	err = r.eachHitScan(f, func(id string, _ *domain.Meta) error {
+		n, err := r.collectHits(id, f.IncludeSoft404, hosts)
+		if n > 0 {
+			scans++
+		}
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits = make([]domain.PathHit, 0, len(hosts))
+	for p, hs := range hosts {
+		if len(hs) < f.MinHosts {
+			continue
+		}
+		hits = append(hits, domain.PathHit{Path: p, Hosts: len(hs)})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Hosts != hits[j].Hosts {
+			return hits[i].Hosts > hits[j].Hosts
+		}
+		return hits[i].Path < hits[j].Path
+	})
+	return hits, scans, nil
+}
+
+// HitScans lists every stored scan matching f, including those without findings.
+func (r *ScanRepo) HitScans(f domain.HitsFilter) ([]domain.HitScan, error) {
+	var out []domain.HitScan
+	err := r.eachHitScan(f, func(_ string, m *domain.Meta) error {
+		hs := domain.HitScan{WordlistID: m.WordlistID}
+		for t := range m.Hosts {
+			hs.Hosts = append(hs.Hosts, hitHost(t))
+		}
+		if len(hs.Hosts) == 0 {
+			for _, t := range m.Targets {
+				hs.Hosts = append(hs.Hosts, hitHost(t))
+			}
+		}
+		out = append(out, hs)
+		return nil
+	})
+	return out, err
+}
+
+// eachHitScan calls fn for every stored scan whose meta matches f's tags and time range.
+func (r *ScanRepo) eachHitScan(f domain.HitsFilter, fn func(id string, m *domain.Meta) error) error {
+	ents, err := os.ReadDir(r.Dir())
+	if err != nil {
+		return err
+	}
+
+	since, _ := domain.ParseHitsTime(f.Since)
+	until, _ := domain.ParseHitsTime(f.Until)
+
+	for _, e := range ents {
+		id := strings.TrimSuffix(e.Name(), ".json")
+		if e.IsDir() || id == e.Name() || !domain.IsValidScanID(id) {
+			continue
+		}
+
+		var m domain.Meta
+		if err := r.scans.ReadMeta(id, &m); err != nil {
+			continue
+		}
+		if !hitsScanMatches(&m, f.Tags, since, until) {
+			continue
+		}
+		if err := fn(id, &m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hitHost is the form hosts are counted in, so findings and scan targets compare equal.
+func hitHost(target string) string {
+	return strings.ToLower(strings.TrimRight(target, "/"))
+}
+
+func hitsScanMatches(m *domain.Meta, tags []string, since, until time.Time) bool {
+	if len(tags) > 0 {
+		found := false
+		for _, want := range tags {
+			for _, t := range m.Tags {
+				if strings.EqualFold(t, want) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if since.IsZero() && until.IsZero() {
+		return true
+	}
+	started, err := time.Parse(time.RFC3339, m.StartedAt)
+	if err != nil {
+		return false
+	}
+	if !since.IsZero() && started.Before(since) {
+		return false
+	}
+	if !until.IsZero() && started.After(until) {
+		return false
+	}
+	return true
+}
+
+// collectHits adds one scan's findings into hosts (path -> set of hosts) and returns how many it read.
+// Bypass findings are skipped: their paths are mutations of a protected path, not words to learn.
+func (r *ScanRepo) collectHits(scanID string, includeSoft404 bool, hosts map[string]map[string]struct{}) (int, error) {
+	fh, err := os.Open(r.FindingsPath(scanID))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer fh.Close()
+
+	n := 0
+	sc := bufio.NewScanner(fh)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var f domain.Finding
+		if err := json.Unmarshal(sc.Bytes(), &f); err != nil {
+			continue
+		}
+		if f.Technique != "" || (f.Soft404Likely && !includeSoft404) {
+			continue
+		}
+		p := normalizeLine(f.Path)
+		if p == "" {
+			continue
+		}
+		host := hitHost(f.Target)
+
+		hs := hosts[p]
+		if hs == nil {
+			hs = map[string]struct{}{}
+			hosts[p] = hs
+		}
+		hs[host] = struct{}{}
+		n++
+	}
+	return n, sc.Err()
+}
+
+type HitsStore struct {
+	path      string
+	ratesPath string // hit rates counted by the last build, next to the state file
+	mu        sync.Mutex
+}
+
+func NewHitsStore(path string) *HitsStore {
+	return &HitsStore{path: path, ratesPath: filepath.Join(filepath.Dir(path), "hits_rates.json")}
+}
+
+// Load returns the persisted state; a missing file yields an unconfigured state.
+func (s *HitsStore) Load() (*domain.HitsState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &domain.HitsState{}, nil
+		}
+		return nil, err
+	}
+	var st domain.HitsState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (s *HitsStore) Save(st domain.HitsState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeJSONAtomic(s.path, st)
+}
+
+// HitRates returns the hits saved by SaveRates; nil when none have been saved.
+func (s *HitsStore) HitRates() ([]domain.PathHit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := os.ReadFile(s.ratesPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	hits := []domain.PathHit{}
+	if err := json.Unmarshal(b, &hits); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// SaveRates replaces the cached hits and their probed counts.
+func (s *HitsStore) SaveRates(hits []domain.PathHit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hits == nil {
+		hits = []domain.PathHit{}
+	}
+	return writeJSONAtomic(s.ratesPath, hits)
+}
+
+// CountProbed sets each hit's Probed to the distinct hosts of the scans whose wordlist holds its
+// path. Every wordlist is streamed once; lists deleted since are skipped, so their scans only
+// count where they found something.
+func (s *WordlistStore) CountProbed(ctx context.Context, hits []domain.PathHit, scans []domain.HitScan) error {
+	probed := make(map[string]map[string]struct{}, len(hits))
+	for _, h := range hits {
+		probed[h.Path] = map[string]struct{}{}
+	}
+
+	byList := map[string][]int{}
+	for i, sc := range scans {
+		if sc.WordlistID != "" {
+			byList[sc.WordlistID] = append(byList[sc.WordlistID], i)
+		}
+	}
+	for id, idx := range byList {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pf, err := s.OpenPaths(id)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		err = countListHosts(ctx, pf.Cursor(), probed, func(set map[string]struct{}) {
+			for _, i := range idx {
+				for _, host := range scans[i].Hosts {
+					set[host] = struct{}{}
+				}
+			}
+		})
+		_ = pf.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range hits {
+		hits[i].Probed = len(probed[hits[i].Path])
+	}
+	return nil
+}
+
+// countListHosts calls add with the host set of every tracked path the cursor yields.
+func countListHosts(ctx context.Context, cur domain.PathCursor, probed map[string]map[string]struct{}, add func(map[string]struct{})) error {
+	for n := 0; ; n++ {
+		if n%4096 == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		p, ok := cur.Next()
+		if !ok {
+			return cur.Err()
+		}
+		if set, ok := probed[p]; ok {
+			add(set)
+		}
+	}
+}
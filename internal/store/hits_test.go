@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+func TestCollectHits(t *testing.T) {
+	dir := t.TempDir()
+	ss, err := NewScanStore(filepath.Join(dir, "scans"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewScanRepo(dir, ss)
+
+	findings := []domain.Finding{
+		{Target: "https://a.test", Path: "/admin"},
+		{Target: "https://b.test:443", Path: "admin"},
+		{Target: "https://a.test", Path: "/old", Soft404Likely: true},
+		{Target: "https://a.test", Path: "/%2e/admin", Technique: "dot-segment"},
+		{Target: "https://a.test", Path: "/0123456789abcdef", Technique: "x-original-url"},
+	}
+	path := r.FindingsPath("s1")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fh, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := json.NewEncoder(fh)
+	for _, f := range findings {
+		if err := enc.Encode(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fh.Close()
+
+	tests := []struct {
+		includeSoft404 bool
+		want           map[string]int
+	}{
+		{false, map[string]int{"/admin": 2}},
+		{true, map[string]int{"/admin": 2, "/old": 1}},
+	}
+	for _, tt := range tests {
+		hosts := map[string]map[string]struct{}{}
+		if _, err := r.collectHits("s1", tt.includeSoft404, hosts); err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]int{}
+		for p, hs := range hosts {
+			got[p] = len(hs)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("includeSoft404=%v: hits = %v, want %v", tt.includeSoft404, got, tt.want)
+		}
+	}
+}
+
+func TestCountProbed(t *testing.T) {
+	ws, err := NewWordlistStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	small := putWordlist(t, ws, "admin\nbackup\n")
+	big := putWordlist(t, ws, "admin\nlogin\nbackup\nconfig\n")
+
+	hits := []domain.PathHit{
+		{Path: "/admin", Hosts: 3},
+		{Path: "/login", Hosts: 1},
+		{Path: "/spidered", Hosts: 1},
+	}
+	scans := []domain.HitScan{
+		{WordlistID: small, Hosts: []string{"https://a.test", "https://b.test"}},
+		{WordlistID: big, Hosts: []string{"https://b.test", "https://c.test", "https://d.test"}},
+		{WordlistID: "0000000000000000000000000000000000000000000000000000000000000000", Hosts: []string{"https://e.test"}},
+	}
+	if err := ws.CountProbed(context.Background(), hits, scans); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{4, 3, 0} {
+		if hits[i].Probed != want {
+			t.Errorf("%s: Probed = %d, want %d", hits[i].Path, hits[i].Probed, want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ws.CountProbed(ctx, hits, scans); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled CountProbed error = %v", err)
+	}
+
+	hs := NewHitsStore(filepath.Join(t.TempDir(), "hits.json"))
+	if got, err := hs.HitRates(); err != nil || got != nil {
+		t.Fatalf("HitRates before save = %v, %v", got, err)
+	}
+	if err := hs.SaveRates(hits); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := hs.HitRates(); err != nil || !reflect.DeepEqual(got, hits) {
+		t.Errorf("HitRates = %v, %v, want %v", got, err, hits)
+	}
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"hash/fnv"
+	"hash/maphash"
+)
+
+// lineSetExactBytes is how much line text a lineSet keeps verbatim before switching to hashes.
+const lineSetExactBytes = 64 << 20
+
+type lineKey [2]uint64
+
+// lineSet dedupes lines. Ordinary wordlists are compared exactly; past lineSetExactBytes the set
+// switches to 128-bit keys (FNV-1a plus a seeded maphash) so huge lists stay small in memory.
+// Two distinct lines sharing both halves is far less likely than a disk error.
+type lineSet struct {
+	exact  map[string]struct{}
+	bytes  int
+	hashed map[lineKey]struct{}
+	seed   maphash.Seed
+}
+
+func newLineSet() *lineSet {
+	return &lineSet{exact: make(map[string]struct{}, 4096), seed: maphash.MakeSeed()}
+}
+
+// add inserts line and reports whether it was new.
+func (s *lineSet) add(line string) bool {
+	if s.hashed == nil {
+		if _, ok := s.exact[line]; ok {
+			return false
+		}
+		s.exact[line] = struct{}{}
+		s.bytes += len(line)
+		if s.bytes > lineSetExactBytes {
+			s.spill()
+		}
+		return true
+	}
+	k := s.key(line)
+	if _, ok := s.hashed[k]; ok {
+		return false
+	}
+	s.hashed[k] = struct{}{}
+	return true
+}
+
+func (s *lineSet) has(line string) bool {
+	if s.hashed == nil {
+		_, ok := s.exact[line]
+		return ok
+	}
+	_, ok := s.hashed[s.key(line)]
+	return ok
+}
+
+// spill moves the exact set to hashed keys.
+func (s *lineSet) spill() {
+	s.hashed = make(map[lineKey]struct{}, len(s.exact)*2)
+	for line := range s.exact {
+		s.hashed[s.key(line)] = struct{}{}
+	}
+	s.exact = nil
+}
+
+func (s *lineSet) key(line string) lineKey {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(line))
+	var mh maphash.Hash
+	mh.SetSeed(s.seed)
+	_, _ = mh.WriteString(line)
+	return lineKey{h.Sum64(), mh.Sum64()}
+}
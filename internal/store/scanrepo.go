@@ -140,3 +140,29 @@ func (r *ScanRepo) ErrorsPage(scanID string, cursor int64, limit int) (ndjson.ND
 func (r *ScanRepo) LogPage(scanID string, cursor int64, limit int) (ndjson.NDJSONPage[domain.Probe], error) {
 	return ndjson.ReadNDJSONFromOffset[domain.Probe](r.probePathForScan(scanID), cursor, limit)
 }
+
+// ScansUsingWordlist returns the IDs of persisted scans that were started with wordlistID.
+func (r *ScanRepo) ScansUsingWordlist(wordlistID string) ([]string, error) {
+	ents, err := os.ReadDir(r.Dir())
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for _, e := range ents {
+		id := strings.TrimSuffix(e.Name(), ".json")
+		if e.IsDir() || id == e.Name() || !domain.IsValidScanID(id) {
+			continue
+		}
+		var m struct {
+			WordlistID string `json:"wordlistId"`
+		}
+		if err := r.scans.ReadMeta(id, &m); err != nil {
+			continue
+		}
+		if m.WordlistID == wordlistID {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+type ScopeStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewScopeStore(path string) *ScopeStore {
+	return &ScopeStore{path: path}
+}
+
+// Load returns the persisted scope; a missing file yields an empty (unrestricted) scope.
+func (s *ScopeStore) Load() (*domain.Scope, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &domain.Scope{}, nil
+		}
+		return nil, err
+	}
+	var sc domain.Scope
+	if err := json.Unmarshal(b, &sc); err != nil {
+		return nil, err
+	}
+	return &sc, nil
+}
+
+func (s *ScopeStore) Save(sc domain.Scope) (domain.Scope, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := writeJSONAtomic(s.path, sc); err != nil {
+		return domain.Scope{}, err
+	}
+	return sc, nil
+}
+
+// LoadScope is Load for the scanner; it fails fast when ctx is already done.
+func (s *ScopeStore) LoadScope(ctx context.Context) (*domain.Scope, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.Load()
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// TechRuleStore persists custom fingerprint rules.
+type TechRuleStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewTechRuleStore(path string) *TechRuleStore {
+	return &TechRuleStore{path: path}
+}
+
+// Load returns the persisted custom rules; a missing file yields an empty set.
+func (s *TechRuleStore) Load() (*domain.TechRuleSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &domain.TechRuleSet{Rules: []domain.TechRule{}}, nil
+		}
+		return nil, err
+	}
+	var rs domain.TechRuleSet
+	if err := json.Unmarshal(b, &rs); err != nil {
+		return nil, err
+	}
+	if rs.Rules == nil {
+		rs.Rules = []domain.TechRule{}
+	}
+	return &rs, nil
+}
+
+func (s *TechRuleStore) Save(rs domain.TechRuleSet) (domain.TechRuleSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rs.Rules == nil {
+		rs.Rules = []domain.TechRule{}
+	}
+	rs.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := writeJSONAtomic(s.path, rs); err != nil {
+		return domain.TechRuleSet{}, err
+	}
+	return rs, nil
+}
+
+func (s *TechRuleStore) LoadTechRules(ctx context.Context) ([]domain.TechRule, error) {
+	_ = ctx
+	rs, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	return rs.Rules, nil
+}
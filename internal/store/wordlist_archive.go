@@ -0,0 +1,99 @@
+package store
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+type PutResult struct {
+	ID    string `json:"wordlistId"`
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+}
+
+// IsArchiveName reports whether PutUpload would unpack a file of this name rather than store it as is.
+func IsArchiveName(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".gz") || strings.HasSuffix(lower, ".zip")
+}
+
+// PutUpload stores an uploaded file, unpacking .gz and .zip archives on the way.
+// Zip entries are stored as separate wordlists; other files go straight to Put.
+func (s *WordlistStore) PutUpload(name string, r io.Reader) ([]PutResult, error) {
+	lower := strings.ToLower(name)
+
+	switch {
+	case strings.HasSuffix(lower, ".gz"):
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		inner := strings.TrimSpace(zr.Name)
+		if inner == "" {
+			inner = name[:len(name)-len(".gz")]
+		}
+		id, n, err := s.Put(inner, zr)
+		if err != nil {
+			return nil, err
+		}
+		return []PutResult{{ID: id, Name: inner, Bytes: n}}, nil
+
+	case strings.HasSuffix(lower, ".zip"):
+		return s.putZip(r)
+
+	default:
+		id, n, err := s.Put(name, r)
+		if err != nil {
+			return nil, err
+		}
+		return []PutResult{{ID: id, Name: name, Bytes: n}}, nil
+	}
+}
+
+// putZip spools the archive to disk (zip needs random access) and stores each file entry.
+func (s *WordlistStore) putZip(r io.Reader) ([]PutResult, error) {
+	tmp, err := os.CreateTemp(s.dir, "upload-*.zip.tmp")
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PutResult, 0, len(zr.File))
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(path.Base(f.Name), ".") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		name := path.Base(f.Name)
+		id, n, err := s.Put(name, rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, PutResult{ID: id, Name: name, Bytes: n})
+	}
+	return out, nil
+}
@@ -0,0 +1,209 @@
+package store
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// lineOp transforms one line; ok=false drops it.
+type lineOp func(line string) (out string, ok bool)
+
+// Derive streams the merged sources through req.Ops into a new content-addressed wordlist
+// and records the lineage on its meta. req must already be validated. When the output matches a
+// list already stored, such as a source the ops left unchanged, that list is returned as it is:
+// its names and lineage are not rewritten.
+func (s *WordlistStore) Derive(req domain.DeriveWordlistRequest) (res PutResult, err error) {
+	for _, id := range req.Sources {
+		if !s.Exists(id) {
+			return PutResult{}, fmt.Errorf("wordlist %s: %w", id, os.ErrNotExist)
+		}
+	}
+
+	ops := make([]lineOp, 0, len(req.Ops))
+	for _, op := range req.Ops {
+		fn, err := s.compileOp(op)
+		if err != nil {
+			return PutResult{}, err
+		}
+		ops = append(ops, fn)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(s.writeDerived(pw, req.Sources, ops))
+	}()
+
+	id, n, existed, err := s.put(req.Name, pr, false)
+	_ = pr.Close()
+	if err != nil {
+		return PutResult{}, err
+	}
+
+	var m *domain.WordlistMeta
+	if existed {
+		m, err = s.Meta(id)
+	} else {
+		m, err = s.updateMeta(id, func(m *domain.WordlistMeta) {
+			m.Lineage = &domain.WordlistLineage{
+				Sources:   req.Sources,
+				Ops:       req.Ops,
+				CreatedAt: time.Now().UTC().Format(time.RFC3339),
+			}
+		})
+	}
+	if err != nil {
+		return PutResult{}, err
+	}
+
+	name := id[:12] + ".txt"
+	if len(m.Names) > 0 {
+		name = m.Names[0]
+	}
+	return PutResult{ID: id, Name: name, Bytes: n}, nil
+}
+
+// writeDerived emits each surviving line once; dedupe uses the scan-time normalised form.
+func (s *WordlistStore) writeDerived(w io.Writer, sources []string, ops []lineOp) error {
+	bw := bufio.NewWriterSize(w, 256*1024)
+	seen := newLineSet()
+
+	for _, id := range sources {
+		path, err := s.contentFile(id)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		sc := bufio.NewScanner(f)
+		sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	lines:
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" {
+				continue
+			}
+			for _, op := range ops {
+				var ok bool
+				if line, ok = op(line); !ok || line == "" {
+					continue lines
+				}
+			}
+
+			if !seen.add(normalizeLine(line)) {
+				continue
+			}
+
+			if _, err := bw.WriteString(line); err != nil {
+				f.Close()
+				return err
+			}
+			if err := bw.WriteByte('\n'); err != nil {
+				f.Close()
+				return err
+			}
+		}
+		err = sc.Err()
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func (s *WordlistStore) compileOp(op domain.WordlistOp) (lineOp, error) {
+	switch op.Op {
+	case domain.WordlistOpSubtract:
+		drop, err := s.pathSet(op.WordlistIDs)
+		if err != nil {
+			return nil, err
+		}
+		return func(line string) (string, bool) {
+			return line, !drop.has(normalizeLine(line))
+		}, nil
+
+	case domain.WordlistOpFilter:
+		re, err := regexp.Compile(op.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		return func(line string) (string, bool) {
+			return line, re.MatchString(line) != op.Exclude
+		}, nil
+
+	case domain.WordlistOpExtension:
+		exts := make(map[string]struct{}, len(op.Extensions))
+		for _, e := range op.Extensions {
+			exts[e] = struct{}{}
+		}
+		return func(line string) (string, bool) {
+			p := line
+			if i := strings.IndexAny(p, "?#"); i >= 0 {
+				p = p[:i]
+			}
+			_, found := exts[strings.ToLower(path.Ext(p))]
+			return line, found != op.Exclude
+		}, nil
+
+	case domain.WordlistOpLowercase:
+		return func(line string) (string, bool) { return strings.ToLower(line), true }, nil
+
+	case domain.WordlistOpPrefix:
+		return func(line string) (string, bool) { return op.Value + line, true }, nil
+
+	case domain.WordlistOpSuffix:
+		return func(line string) (string, bool) { return line + op.Value, true }, nil
+
+	case domain.WordlistOpStripComments:
+		return func(line string) (string, bool) {
+			if strings.HasPrefix(line, "#") {
+				return "", false
+			}
+			if i := strings.Index(line, " #"); i >= 0 {
+				line = strings.TrimSpace(line[:i])
+			}
+			return line, line != ""
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown wordlist op %q", op.Op)
+}
+
+// pathSet loads the normalised entries of ids into a lineSet, as buildPaths dedupes.
+func (s *WordlistStore) pathSet(ids []string) (*lineSet, error) {
+	out := newLineSet()
+	for _, id := range ids {
+		if !s.Exists(id) {
+			return nil, fmt.Errorf("wordlist %s: %w", id, os.ErrNotExist)
+		}
+		pf, err := s.OpenPaths(id)
+		if err != nil {
+			return nil, err
+		}
+		cur := pf.Cursor()
+		for {
+			line, ok := cur.Next()
+			if !ok {
+				break
+			}
+			out.add(line)
+		}
+		err = cur.Err()
+		pf.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,157 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+func TestDerive(t *testing.T) {
+	s, err := NewWordlistStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := putWordlist(t, s, "# comment\nAdmin\nlogin.php\nindex.html # home\nbackup.ZIP\n/api\nadmin\napi?x=1\n")
+	other := putWordlist(t, s, "/login.php\napi\n")
+
+	tests := []struct {
+		name    string
+		sources []string
+		ops     []domain.WordlistOp
+		want    string
+	}{
+		{
+			name:    "merge dedupes on the normalised form",
+			sources: []string{other, base},
+			want:    "/login.php\napi\n# comment\nAdmin\nindex.html # home\nbackup.ZIP\nadmin\napi?x=1\n",
+		},
+		{
+			name:    "strip comments",
+			sources: []string{base},
+			ops:     []domain.WordlistOp{{Op: domain.WordlistOpStripComments}},
+			want:    "Admin\nlogin.php\nindex.html\nbackup.ZIP\n/api\nadmin\napi?x=1\n",
+		},
+		{
+			name:    "subtract",
+			sources: []string{base},
+			ops:     []domain.WordlistOp{{Op: domain.WordlistOpStripComments}, {Op: domain.WordlistOpSubtract, WordlistIDs: []string{other}}},
+			want:    "Admin\nindex.html\nbackup.ZIP\nadmin\napi?x=1\n",
+		},
+		{
+			name:    "lowercase then dedupe",
+			sources: []string{base},
+			ops:     []domain.WordlistOp{{Op: domain.WordlistOpStripComments}, {Op: domain.WordlistOpLowercase}},
+			want:    "admin\nlogin.php\nindex.html\nbackup.zip\n/api\napi?x=1\n",
+		},
+		{
+			name:    "filter",
+			sources: []string{base},
+			ops:     []domain.WordlistOp{{Op: domain.WordlistOpFilter, Pattern: `^/?api`}},
+			want:    "/api\napi?x=1\n",
+		},
+		{
+			name:    "filter exclude",
+			sources: []string{base},
+			ops:     []domain.WordlistOp{{Op: domain.WordlistOpFilter, Pattern: `(?i)admin|#`, Exclude: true}},
+			want:    "login.php\nbackup.ZIP\n/api\napi?x=1\n",
+		},
+		{
+			name:    "extension ignores case and query",
+			sources: []string{base},
+			ops:     []domain.WordlistOp{{Op: domain.WordlistOpExtension, Extensions: []string{"zip", ".PHP"}}},
+			want:    "login.php\nbackup.ZIP\n",
+		},
+		{
+			name:    "extension exclude",
+			sources: []string{base},
+			ops: []domain.WordlistOp{
+				{Op: domain.WordlistOpStripComments},
+				{Op: domain.WordlistOpExtension, Extensions: []string{"php", "zip", "html"}, Exclude: true},
+			},
+			want: "Admin\n/api\nadmin\napi?x=1\n",
+		},
+		{
+			name:    "prefix and suffix",
+			sources: []string{other},
+			ops:     []domain.WordlistOp{{Op: domain.WordlistOpPrefix, Value: "old"}, {Op: domain.WordlistOpSuffix, Value: ".bak"}},
+			want:    "old/login.php.bak\noldapi.bak\n",
+		},
+	}
+	for _, tt := range tests {
+		req := domain.DeriveWordlistRequest{Name: tt.name, Sources: tt.sources, Ops: tt.ops}
+		if details := req.NormalizeAndValidate(); len(details) > 0 {
+			t.Fatalf("%s: invalid request: %v", tt.name, details)
+		}
+		res, err := s.Derive(req)
+		if err != nil {
+			t.Fatalf("%s: Derive: %v", tt.name, err)
+		}
+		b, err := os.ReadFile(s.ContentPath(res.ID))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, b, tt.want)
+		}
+
+		m, err := s.Meta(res.ID)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if m.Lineage == nil || len(m.Lineage.Sources) != len(tt.sources) || len(m.Lineage.Ops) != len(tt.ops) {
+			t.Errorf("%s: lineage = %+v", tt.name, m.Lineage)
+		}
+	}
+}
+
+func TestDeriveMissingSource(t *testing.T) {
+	s, err := NewWordlistStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := putWordlist(t, s, "a\n")
+	missing := "0000000000000000000000000000000000000000000000000000000000000000"
+
+	for _, req := range []domain.DeriveWordlistRequest{
+		{Sources: []string{base, missing}},
+		{Sources: []string{base}, Ops: []domain.WordlistOp{{Op: domain.WordlistOpSubtract, WordlistIDs: []string{missing}}}},
+	} {
+		if _, err := s.Derive(req); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Derive(%+v) error = %v, want not exist", req, err)
+		}
+	}
+}
+
+func TestDeriveExistingOutput(t *testing.T) {
+	s, err := NewWordlistStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := putWordlist(t, s, "admin\nlogin\n")
+
+	req := domain.DeriveWordlistRequest{
+		Name:    "lowered",
+		Sources: []string{base},
+		Ops:     []domain.WordlistOp{{Op: domain.WordlistOpLowercase}},
+	}
+	if details := req.NormalizeAndValidate(); len(details) > 0 {
+		t.Fatalf("invalid request: %v", details)
+	}
+	res, err := s.Derive(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != base || res.Name != "test.txt" {
+		t.Errorf("Derive = %+v, want the source %s unchanged", res, base)
+	}
+
+	m, err := s.Meta(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Lineage != nil || len(m.Names) != 1 {
+		t.Errorf("source meta rewritten: names %v, lineage %+v", m.Names, m.Lineage)
+	}
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// ImportRoot is a read-only directory on the server (e.g. /usr/share/seclists) that
+// wordlists can be registered from without uploading them through the browser.
+type ImportRoot struct {
+	dir string
+}
+
+func NewImportRoot(dir string) (*ImportRoot, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	real, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(real)
+	if err != nil {
+		return nil, err
+	}
+	if !st.IsDir() {
+		return nil, errors.New("import root is not a directory")
+	}
+	return &ImportRoot{dir: filepath.Clean(real)}, nil
+}
+
+func (r *ImportRoot) Dir() string { return r.dir }
+
+// Resolve maps a root-relative path to an absolute one, rejecting anything (including symlink
+// targets) that lands outside the root.
+func (r *ImportRoot) Resolve(rel string) (string, bool) {
+	c := filepath.Clean(filepath.Join(r.dir, filepath.FromSlash(strings.TrimSpace(rel))))
+	if c != r.dir && !strings.HasPrefix(c, r.dir+string(os.PathSeparator)) {
+		return "", false
+	}
+	real, err := filepath.EvalSymlinks(c)
+	if err != nil {
+		return "", false
+	}
+	if real != r.dir && !strings.HasPrefix(real, r.dir+string(os.PathSeparator)) {
+		return "", false
+	}
+	return real, true
+}
+
+type ImportEntry struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"` // relative to the import root, slash separated
+	Dir   bool   `json:"dir"`
+	Bytes int64  `json:"bytes,omitempty"`
+}
+
+func (r *ImportRoot) List(rel string) ([]ImportEntry, error) {
+	abs, ok := r.Resolve(rel)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	ents, err := os.ReadDir(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]ImportEntry, 0, len(ents))
+	for _, e := range ents {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		p := filepath.Join(abs, e.Name())
+		relPath, err := filepath.Rel(r.dir, p)
+		if err != nil {
+			continue
+		}
+		if _, ok := r.Resolve(relPath); !ok {
+			continue // symlink leading out of the root
+		}
+		st, err := os.Stat(p) // follows symlinks
+		if err != nil {
+			continue
+		}
+		ent := ImportEntry{Name: e.Name(), Path: filepath.ToSlash(relPath), Dir: st.IsDir()}
+		if !st.IsDir() {
+			if !st.Mode().IsRegular() {
+				continue
+			}
+			ent.Bytes = st.Size()
+		}
+		out = append(out, ent)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Dir != out[j].Dir {
+			return out[i].Dir
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+// ErrArchiveReference is returned when an archive is registered by reference; archives must be
+// imported by copy so they are unpacked.
+var ErrArchiveReference = errors.New("archives cannot be referenced; import them by copy")
+
+// ErrStaleReference is returned for a referenced wordlist whose source file changed since it was hashed.
+var ErrStaleReference = errors.New("referenced wordlist file changed since it was imported")
+
+// Reference registers absPath without copying it. The ID is still the SHA-256 of the content,
+// and the normalised .paths sidecar is built from the source file.
+func (s *WordlistStore) Reference(absPath string) (id string, size int64, err error) {
+	if IsArchiveName(absPath) {
+		return "", 0, ErrArchiveReference
+	}
+	f, err := os.Open(absPath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	if err := s.upsertMeta(sum, filepath.Base(absPath), n); err != nil {
+		return "", 0, err
+	}
+
+	if _, statErr := os.Stat(s.ContentPath(sum)); errors.Is(statErr, os.ErrNotExist) {
+		_, err := s.updateMeta(sum, func(m *domain.WordlistMeta) {
+			m.SourcePath = absPath
+			m.SourceSize = st.Size()
+			m.SourceModTime = st.ModTime().UTC().Format(time.RFC3339Nano)
+			m.Stale = false
+		})
+		if err != nil {
+			return "", 0, err
+		}
+	}
+
+	return sum, n, s.ensurePaths(sum)
+}
+
+// contentFile returns the stored copy of a wordlist, or its referenced source path. A referenced
+// file whose size or mtime changed since it was hashed is marked stale and ErrStaleReference returned.
+func (s *WordlistStore) contentFile(id string) (string, error) {
+	cp := s.ContentPath(id)
+	if _, err := os.Stat(cp); err == nil {
+		return cp, nil
+	}
+	m, err := s.Meta(id)
+	if err != nil || m.SourcePath == "" {
+		return cp, nil
+	}
+	if m.Stale {
+		return "", ErrStaleReference
+	}
+	st, err := os.Stat(m.SourcePath)
+	if err != nil {
+		return "", err
+	}
+	if m.SourceModTime != "" &&
+		(st.Size() != m.SourceSize || st.ModTime().UTC().Format(time.RFC3339Nano) != m.SourceModTime) {
+		_, _ = s.updateMeta(id, func(m *domain.WordlistMeta) { m.Stale = true })
+		return "", ErrStaleReference
+	}
+	return m.SourcePath, nil
+}
+
+// Exists reports whether the wordlist is stored or referenced.
+func (s *WordlistStore) Exists(id string) bool {
+	if _, err := os.Stat(s.ContentPath(id)); err == nil {
+		return true
+	}
+	m, err := s.Meta(id)
+	return err == nil && m.SourcePath != ""
+}
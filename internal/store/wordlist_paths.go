@@ -0,0 +1,241 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+// PathsPath is the normalised sidecar of a wordlist: one "/"-prefixed, deduplicated entry per line.
+func (s *WordlistStore) PathsPath(id string) string { return filepath.Join(s.dir, id+".paths") }
+
+// normalizeLine applies the scan-time path rules; "" means skip.
+func normalizeLine(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return ""
+	}
+	if !strings.HasPrefix(line, "/") {
+		line = "/" + line
+	}
+	return line
+}
+
+// buildPaths writes the normalised sidecar for id and returns its entry count and stats.
+// Dedupe uses a lineSet, so memory stays bounded for huge lists.
+func (s *WordlistStore) buildPaths(id string) (int64, *domain.WordlistStats, error) {
+	path, err := s.contentFile(id)
+	if err != nil {
+		return 0, nil, err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(s.dir, "paths-*.tmp")
+	if err != nil {
+		return 0, nil, err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+	}()
+
+	w := bufio.NewWriterSize(tmp, 256*1024)
+	seen := newLineSet()
+
+	var entries int64
+	st := newStatsBuilder()
+	sc := bufio.NewScanner(src)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	for sc.Scan() {
+		line := normalizeLine(sc.Text())
+		if line == "" {
+			continue
+		}
+		st.lines++
+		if !seen.add(line) {
+			continue
+		}
+
+		if _, err := w.WriteString(line); err != nil {
+			return 0, nil, err
+		}
+		if err := w.WriteByte('\n'); err != nil {
+			return 0, nil, err
+		}
+		entries++
+		st.add(line)
+	}
+	if err := sc.Err(); err != nil {
+		return 0, nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return 0, nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, nil, err
+	}
+	if err := os.Rename(tmpPath, s.PathsPath(id)); err != nil {
+		return 0, nil, err
+	}
+	return entries, st.stats(), nil
+}
+
+// ensurePaths builds the sidecar (and stats) for wordlists stored before they existed.
+func (s *WordlistStore) ensurePaths(id string) error {
+	m, metaErr := s.Meta(id)
+	if _, err := os.Stat(s.PathsPath(id)); err == nil {
+		if metaErr != nil || m.Stats != nil {
+			return nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	entries, stats, err := s.buildPaths(id)
+	if err != nil {
+		return err
+	}
+
+	if metaErr != nil {
+		return nil
+	}
+	_, err = s.updateMeta(id, func(m *domain.WordlistMeta) {
+		m.Entries = entries
+		m.Stats = stats
+	})
+	return err
+}
+
+// OpenPaths opens the normalised sidecar for concurrent reading by many cursors.
+func (s *WordlistStore) OpenPaths(id string) (*PathsFile, error) {
+	if err := s.ensurePaths(id); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.PathsPath(id))
+	if err != nil {
+		return nil, err
+	}
+	return &PathsFile{f: f}, nil
+}
+
+// PathsFile shares one file handle between cursors; each cursor reads with ReadAt at its own offset.
+type PathsFile struct {
+	f *os.File
+}
+
+func (p *PathsFile) Cursor() domain.PathCursor {
+	return &pathCursor{f: p.f}
+}
+
+func (p *PathsFile) Close() error { return p.f.Close() }
+
+const pathCursorChunk = 32 * 1024
+
+type pathCursor struct {
+	f   *os.File
+	off int64
+	buf []byte
+	pos int
+	eof bool
+	err error
+}
+
+func (c *pathCursor) Next() (string, bool) {
+	for {
+		if i := bytes.IndexByte(c.buf[c.pos:], '\n'); i >= 0 {
+			line := string(c.buf[c.pos : c.pos+i])
+			c.pos += i + 1
+			return line, true
+		}
+		if c.eof || c.err != nil {
+			// A final line without a trailing newline.
+			if c.pos < len(c.buf) {
+				line := string(c.buf[c.pos:])
+				c.pos = len(c.buf)
+				return line, true
+			}
+			return "", false
+		}
+		c.fill()
+	}
+}
+
+func (c *pathCursor) fill() {
+	rest := len(c.buf) - c.pos
+	size := pathCursorChunk
+	if rest*2 > size {
+		size = rest * 2
+	}
+	nb := make([]byte, rest, rest+size)
+	copy(nb, c.buf[c.pos:])
+
+	n, err := c.f.ReadAt(nb[rest:rest+size], c.off)
+	c.off += int64(n)
+	c.buf = nb[:rest+n]
+	c.pos = 0
+
+	if errors.Is(err, io.EOF) {
+		c.eof = true
+	} else if err != nil {
+		c.err = err
+	}
+}
+
+func (c *pathCursor) Err() error { return c.err }
+
+// pathsPageScanBudget caps the sidecar bytes one PathsPage call reads, so a filter that rarely
+// matches returns a short page and a cursor instead of scanning a huge list in one request.
+const pathsPageScanBudget = 8 << 20
+
+// PathsPage reads up to limit sidecar entries from byte offset cursor, keeping those match accepts
+// (all of them when match is nil). next is the cursor for the following page; a filtered page can
+// hold fewer than limit items (even none) while hasMore is still true.
+func (s *WordlistStore) PathsPage(id string, cursor int64, limit int, match func(string) bool) (items []string, next int64, hasMore bool, err error) {
+	pf, err := s.OpenPaths(id)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	defer pf.Close()
+
+	st, err := pf.f.Stat()
+	if err != nil {
+		return nil, 0, false, err
+	}
+	size := st.Size()
+	if cursor > size {
+		cursor = size
+	}
+
+	c := &pathCursor{f: pf.f, off: cursor}
+	next = cursor
+	items = make([]string, 0, limit)
+	for len(items) < limit && next-cursor < pathsPageScanBudget {
+		line, ok := c.Next()
+		if !ok {
+			break
+		}
+		next += int64(len(line)) + 1
+		if match == nil || match(line) {
+			items = append(items, line)
+		}
+	}
+	if err := c.Err(); err != nil {
+		return nil, 0, false, err
+	}
+	if next > size {
+		next = size
+	}
+	return items, next, next < size, nil
+}
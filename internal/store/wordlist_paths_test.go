@@ -0,0 +1,142 @@
+package store
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func putWordlist(t *testing.T, s *WordlistStore, content string) string {
+	t.Helper()
+	id, _, err := s.Put("test.txt", strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	return id
+}
+
+func TestBuildPaths(t *testing.T) {
+	s, err := NewWordlistStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := putWordlist(t, s, "admin\n/admin\n\n  login  \r\nfoo/bar\nadmin\n/\n")
+
+	b, err := os.ReadFile(s.PathsPath(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "/admin\n/login\n/foo/bar\n/\n"; got != want {
+		t.Errorf("sidecar = %q, want %q", got, want)
+	}
+
+	m, err := s.Meta(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Entries != 4 {
+		t.Errorf("Entries = %d, want 4", m.Entries)
+	}
+	if m.Stats == nil || m.Stats.Lines != 6 {
+		t.Errorf("Stats = %+v, want 6 lines", m.Stats)
+	}
+}
+
+func TestPathsPage(t *testing.T) {
+	s, err := NewWordlistStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := putWordlist(t, s, "a\nbb\nccc\nadmin\n")
+	// Sidecar: "/a\n/bb\n/ccc\n/admin\n", 19 bytes.
+	const size = 19
+
+	tests := []struct {
+		name     string
+		cursor   int64
+		limit    int
+		match    func(string) bool
+		want     []string
+		wantNext int64
+		wantMore bool
+	}{
+		{"first page", 0, 2, nil, []string{"/a", "/bb"}, 7, true},
+		{"middle page", 7, 1, nil, []string{"/ccc"}, 12, true},
+		{"last page exact", 12, 1, nil, []string{"/admin"}, size, false},
+		{"limit past end", 7, 10, nil, []string{"/ccc", "/admin"}, size, false},
+		{"cursor at end", size, 5, nil, []string{}, size, false},
+		{"cursor past end", size + 100, 5, nil, []string{}, size, false},
+		{"filtered", 0, 5, func(l string) bool { return strings.HasPrefix(l, "/a") }, []string{"/a", "/admin"}, size, false},
+		{"filtered stops at limit", 0, 1, func(l string) bool { return strings.HasPrefix(l, "/a") }, []string{"/a"}, 3, true},
+		{"filtered no match", 0, 5, func(string) bool { return false }, []string{}, size, false},
+	}
+	for _, tt := range tests {
+		items, next, more, err := s.PathsPage(id, tt.cursor, tt.limit, tt.match)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(items, tt.want) || next != tt.wantNext || more != tt.wantMore {
+			t.Errorf("%s: got %q next=%d more=%v, want %q next=%d more=%v",
+				tt.name, items, next, more, tt.want, tt.wantNext, tt.wantMore)
+		}
+	}
+}
+
+func TestPathsPageWalk(t *testing.T) {
+	s, err := NewWordlistStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for i := 0; i < 1000; i++ {
+		lines = append(lines, strings.Repeat("x", i%7+1)+"/"+strings.Repeat("y", i%13)+string(rune('a'+i%26))+strings.Repeat("z", i))
+	}
+	id := putWordlist(t, s, strings.Join(lines, "\n"))
+
+	var got []string
+	var cursor int64
+	for pages := 0; ; pages++ {
+		if pages > 1000 {
+			t.Fatal("cursor does not advance")
+		}
+		items, next, more, err := s.PathsPage(id, cursor, 37, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, items...)
+		if !more {
+			break
+		}
+		cursor = next
+	}
+	if len(got) != len(lines) {
+		t.Fatalf("walked %d entries, want %d", len(got), len(lines))
+	}
+	for i, l := range lines {
+		if got[i] != "/"+l {
+			t.Fatalf("entry %d = %q, want %q", i, got[i], "/"+l)
+		}
+	}
+}
+
+func TestLineSet(t *testing.T) {
+	s := newLineSet()
+	for _, l := range []string{"/a", "/b", "/a"} {
+		s.add(l)
+	}
+	if s.add("/a") || !s.add("/c") {
+		t.Fatal("exact set: wrong add result")
+	}
+
+	s.spill()
+	if s.exact != nil || len(s.hashed) != 3 {
+		t.Fatalf("spill kept %d hashed keys", len(s.hashed))
+	}
+	if s.add("/b") {
+		t.Error("add after spill: /b reported new")
+	}
+	if !s.add("/d") || s.add("/d") {
+		t.Error("add after spill: /d not deduped")
+	}
+}
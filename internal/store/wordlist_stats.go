@@ -0,0 +1,100 @@
+package store
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Pusher91/webtruder/internal/domain"
+)
+
+const (
+	statsTopExtensions = 15
+	statsMaxDepth      = 8
+	// statsMaxExtKeys bounds the extension map on lists with many junk "extensions".
+	statsMaxExtKeys = 4096
+)
+
+type statsBuilder struct {
+	lines int64
+	exts  map[string]int64
+	depth []int64
+}
+
+func newStatsBuilder() *statsBuilder {
+	return &statsBuilder{
+		exts:  make(map[string]int64, 64),
+		depth: make([]int64, statsMaxDepth+1),
+	}
+}
+
+// add counts one unique normalised entry.
+func (b *statsBuilder) add(p string) {
+	ext := pathExt(p)
+	if _, ok := b.exts[ext]; ok || len(b.exts) < statsMaxExtKeys {
+		b.exts[ext]++
+	}
+
+	d := pathDepth(p)
+	if d > statsMaxDepth {
+		d = statsMaxDepth
+	}
+	b.depth[d]++
+}
+
+func (b *statsBuilder) stats() *domain.WordlistStats {
+	exts := make([]domain.ExtensionCount, 0, len(b.exts))
+	for e, n := range b.exts {
+		exts = append(exts, domain.ExtensionCount{Ext: e, Count: n})
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		if exts[i].Count != exts[j].Count {
+			return exts[i].Count > exts[j].Count
+		}
+		return exts[i].Ext < exts[j].Ext
+	})
+	if len(exts) > statsTopExtensions {
+		exts = exts[:statsTopExtensions]
+	}
+
+	// Trim empty deep buckets so short lists stay compact.
+	depth := b.depth
+	for len(depth) > 1 && depth[len(depth)-1] == 0 {
+		depth = depth[:len(depth)-1]
+	}
+
+	return &domain.WordlistStats{Lines: b.lines, Extensions: exts, Depth: depth}
+}
+
+// pathExt returns the lowercased extension (with dot) of the last segment, ignoring any query.
+// Anything that does not look like an extension counts as none.
+func pathExt(p string) string {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	p = p[strings.LastIndexByte(p, '/')+1:]
+	i := strings.LastIndexByte(p, '.')
+	if i <= 0 || i == len(p)-1 || len(p)-i > 9 {
+		return ""
+	}
+	ext := strings.ToLower(p[i:])
+	for _, c := range ext[1:] {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ext
+}
+
+// pathDepth counts the non-empty segments of a "/"-prefixed path.
+func pathDepth(p string) int {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	n := 0
+	for _, seg := range strings.Split(p, "/") {
+		if seg != "" {
+			n++
+		}
+	}
+	return n
+}
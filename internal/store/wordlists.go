@@ -1,7 +1,6 @@
 package store
 
 import (
-	"bufio"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
@@ -12,6 +11,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Pusher91/webtruder/internal/domain"
@@ -19,6 +19,10 @@ import (
 
 type WordlistStore struct {
 	dir string
+
+	// metaMu serialises read-modify-write cycles on meta files, which several requests and
+	// imports can touch at once.
+	metaMu sync.Mutex
 }
 
 func NewWordlistStore(dir string) (*WordlistStore, error) {
@@ -46,9 +50,9 @@ func (s *WordlistStore) Meta(id string) (*domain.WordlistMeta, error) {
 	return &m, nil
 }
 
-func (s *WordlistStore) WordlistLines(ctx context.Context, wordlistID string) ([]string, error) {
+func (s *WordlistStore) OpenWordlist(ctx context.Context, wordlistID string) (domain.WordlistSource, error) {
 	_ = ctx
-	return s.ReadLines(wordlistID)
+	return s.OpenPaths(wordlistID)
 }
 
 func (s *WordlistStore) WordlistMeta(ctx context.Context, wordlistID string) (*domain.WordlistMeta, error) {
@@ -65,9 +69,16 @@ func (s *WordlistStore) WordlistMeta(ctx context.Context, wordlistID string) (*d
 }
 
 func (s *WordlistStore) Put(name string, r io.Reader) (id string, size int64, err error) {
+	id, size, _, err = s.put(name, r, true)
+	return id, size, err
+}
+
+// put stores r under its content hash. existed reports that the content was already stored; its
+// meta then only gains name when addName is set.
+func (s *WordlistStore) put(name string, r io.Reader, addName bool) (id string, size int64, existed bool, err error) {
 	tmp, err := os.CreateTemp(s.dir, "upload-*.tmp")
 	if err != nil {
-		return "", 0, err
+		return "", 0, false, err
 	}
 	tmpPath := tmp.Name()
 	defer func() {
@@ -78,29 +89,33 @@ func (s *WordlistStore) Put(name string, r io.Reader) (id string, size int64, er
 	h := sha256.New()
 	n, err := io.Copy(io.MultiWriter(tmp, h), r)
 	if err != nil {
-		return "", 0, err
+		return "", 0, false, err
 	}
 
 	sum := hex.EncodeToString(h.Sum(nil))
 	finalContent := s.ContentPath(sum)
 
 	if err := tmp.Close(); err != nil {
-		return "", 0, err
+		return "", 0, false, err
 	}
 
 	if _, statErr := os.Stat(finalContent); statErr == nil {
-		_ = s.upsertMeta(sum, name, n)
-		return sum, n, nil
+		if addName {
+			_ = s.upsertMeta(sum, name, n)
+		}
+		return sum, n, true, s.ensurePaths(sum)
 	} else if !errors.Is(statErr, os.ErrNotExist) {
-		return "", 0, statErr
+		return "", 0, false, statErr
 	}
 
 	if err := os.Rename(tmpPath, finalContent); err != nil {
-		return "", 0, err
+		return "", 0, false, err
 	}
 
 	_ = s.upsertMeta(sum, name, n)
-	return sum, n, nil
+
+	// Normalise and dedupe once here so scans can stream the list from disk.
+	return sum, n, false, s.ensurePaths(sum)
 }
 
 func (s *WordlistStore) upsertMeta(id, name string, bytes int64) error {
@@ -111,6 +126,9 @@ func (s *WordlistStore) upsertMeta(id, name string, bytes int64) error {
 
 	mp := s.MetaPath(id)
 
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+
 	var m domain.WordlistMeta
 	if b, err := os.ReadFile(mp); err == nil {
 		_ = json.Unmarshal(b, &m)
@@ -174,13 +192,20 @@ func (s *WordlistStore) Delete(id string) (deleted bool, err error) {
 
 	removedAny := false
 
+	if err := os.Remove(s.PathsPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
 	if err := os.Remove(cp); err == nil {
 		removedAny = true
 	} else if !errors.Is(err, os.ErrNotExist) {
 		return false, err
 	}
 
-	if err := os.Remove(mp); err == nil {
+	s.metaMu.Lock()
+	err = os.Remove(mp)
+	s.metaMu.Unlock()
+	if err == nil {
 		removedAny = true
 	} else if !errors.Is(err, os.ErrNotExist) {
 		return false, err
@@ -189,32 +214,33 @@ func (s *WordlistStore) Delete(id string) (deleted bool, err error) {
 	return removedAny, nil
 }
 
-func (s *WordlistStore) ReadLines(id string) ([]string, error) {
-	f, err := os.Open(s.ContentPath(id))
+// updateMeta applies fn to the stored meta of id and writes it back, holding metaMu throughout.
+func (s *WordlistStore) updateMeta(id string, fn func(m *domain.WordlistMeta)) (*domain.WordlistMeta, error) {
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+
+	m, err := s.Meta(id)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-
-	seen := make(map[string]struct{}, 4096)
-	out := make([]string, 0, 1024)
-
-	sc := bufio.NewScanner(f)
-	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	fn(m)
+	if err := writeJSONAtomic(s.MetaPath(id), m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
 
-	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
-		if line == "" {
-			continue
+// Update applies the user-editable fields of req, which must already be validated.
+func (s *WordlistStore) Update(req domain.UpdateWordlistRequest) (*domain.WordlistMeta, error) {
+	return s.updateMeta(req.ID, func(m *domain.WordlistMeta) {
+		if req.DisplayName != nil {
+			m.DisplayName = *req.DisplayName
 		}
-		if !strings.HasPrefix(line, "/") {
-			line = "/" + line
+		if req.Description != nil {
+			m.Description = *req.Description
 		}
-		if _, ok := seen[line]; ok {
-			continue
+		if req.Tags != nil {
+			m.Tags = *req.Tags
 		}
-		seen[line] = struct{}{}
-		out = append(out, line)
-	}
-	return out, sc.Err()
+	})
 }